@@ -3,16 +3,29 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/originbeat-inc/runly-cli/internal/config"
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/internal/ui"
 	"github.com/originbeat-inc/runly-cli/pkg/compiler"
+	"github.com/originbeat-inc/runly-cli/pkg/diff"
 	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/originbeat-inc/runly-cli/pkg/semver"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	buildAgainst string
+	buildStrict  bool
+)
+
+// buildDistFile 构建产物路径，同时作为下一次构建的版本比较基线
+const buildDistFile = "dist.runly"
+
 var buildCmd = &cobra.Command{
 	Use:   "build [file.runly]",
 	Short: "🛠️  Compile, Sign and Solidify the SOP asset",
@@ -26,55 +39,170 @@ var buildCmd = &cobra.Command{
 		// 2. 获取当前 Profile 身份用于签名
 		cfg, _ := config.LoadConfig()
 		profile := cfg.GetActive()
-		if profile.SecretKey == "" {
+		if !hasSigningKey(cfg) {
 			// 提示：未检测到有效密钥，请先运行 runly-cli keys generate
 			ui.PrintError("errors.no_key")
 			os.Exit(1)
 		}
+		if profile.MeID == "" {
+			// 提示：公钥尚未登记，请先运行 runly-cli keys register
+			ui.PrintError("errors.key_unregistered")
+			os.Exit(1)
+		}
 
 		// 3. 加载协议文件
 		proto, err := protocol.Load(file)
 		if err != nil {
 			// 提示：加载协议文件失败
-			ui.PrintError("errors.load_fail", err)
+			ui.PrintError("errors.load_fail", protocol.FormatError(err))
+			os.Exit(1)
+		}
+
+		// 运行时版本门禁：资产要求更高版本的 CLI 时拒绝构建
+		if err := protocol.CheckRuntime(proto); err != nil {
+			ui.PrintError("common.failure", protocol.FormatError(err))
 			os.Exit(1)
 		}
 
 		// 4. 静态语义与 7-Domain 校验
 		ui.PrintStep("cmd.validate_step")
 		if err := protocol.Validate(proto); err != nil {
-			// 校验失败输出
-			fmt.Printf("❌ [%s]: %v\n", i18n.T("common.failure"), err)
+			// 校验失败输出 (file:line:col 格式)
+			fmt.Printf("❌ [%s]:\n%s\n", i18n.T("common.failure"), protocol.FormatError(err))
+			os.Exit(1)
+		}
+
+		// 5. 版本号门禁：与上一次构建 (或 --against 指定的已发布版本) 做协议级比较
+		if !checkVersionBump(file, proto) {
 			os.Exit(1)
 		}
 
-		// 5. 强制确权：同步 Creator 信息为当前环境 MeID
+		// 6. 强制确权：同步 Creator 信息为当前环境 MeID
 		proto.Manifest.Creator.MeID = profile.MeID
 		proto.Manifest.Creator.PubKey = profile.PublicKey
 
-		// 6. 执行编译、哈希计算与数字签名
+		// 片段文件已在加载时合并，清空 includes 使 dist.runly 自包含
+		proto.Includes = nil
+
+		// 凭证拦截：凭证类请求头必须以密钥占位符引用，明文凭证不得进入固化资产
+		if hardcoded := protocol.HardcodedCredentials(proto); len(hardcoded) > 0 {
+			ui.PrintError("errors.secret_hardcoded", strings.Join(hardcoded, ", "))
+			os.Exit(1)
+		}
+
+		// 7. 解锁签名私钥，执行编译、哈希计算与数字签名
+		secretKey := signingKeyOrExit(cfg)
 		ui.PrintStep("cmd.signing_step")
-		hash, err := compiler.BuildArtifact(proto, profile.SecretKey)
+		hash, err := compiler.BuildArtifact(proto, secretKey)
 		if err != nil {
 			ui.PrintError("common.failure", err)
 			os.Exit(1)
 		}
 
-		// 7. 导出固化资产 (dist.runly)
+		// 8. 导出固化资产 (dist.runly)
 		finalData, _ := yaml.Marshal(proto)
-		distFile := "dist.runly"
+
+		// 密钥泄露拦截：已解析的密钥值不得出现在固化资产中
+		if leaked := protocol.DetectSecretLeaks(finalData, proto); len(leaked) > 0 {
+			ui.PrintError("errors.secret_leak", strings.Join(leaked, ", "))
+			os.Exit(1)
+		}
+
+		distFile := buildDistFile
 		if err := os.WriteFile(distFile, finalData, 0644); err != nil {
 			ui.PrintError("errors.load_fail", err)
 			os.Exit(1)
 		}
 
-		// 8. 成功反馈
+		// 9. 成功反馈
 		ui.PrintSuccess("cmd.export_success")
 		fmt.Printf("📄 %s: %s\n", i18n.T("common.output"), distFile)
 		fmt.Printf("🔐 %s:   %s\n", i18n.T("common.hash"), hash)
 	},
 }
 
+// checkVersionBump 按协议级变更判断版本号升级是否到位：破坏性变更未升级主版本号时失败，
+// 其余升级不足给出警告 (--strict 时同样失败)；首次构建或基线属于其他资产时跳过
+func checkVersionBump(file string, proto *protocol.RunlyProtocol) bool {
+	baseline := buildAgainst
+	if baseline == "" {
+		if _, err := os.Stat(buildDistFile); err != nil || samePath(file, buildDistFile) {
+			return true
+		}
+		baseline = buildDistFile
+	}
+
+	prev, err := protocol.Load(baseline)
+	if err != nil {
+		// 显式指定的基线必须可读，默认基线读取失败时仅提示
+		if buildAgainst != "" {
+			ui.PrintError("errors.version_baseline_unreadable", protocol.FormatError(err))
+			return false
+		}
+		ui.PrintWarning("errors.version_baseline_unreadable", protocol.FormatError(err))
+		return true
+	}
+	if prev.Manifest.URN != proto.Manifest.URN {
+		ui.PrintWarning("errors.version_baseline_urn", baseline, prev.Manifest.URN)
+		return true
+	}
+	ui.PrintStep("cmd.version_step", baseline)
+
+	oldV, err := semver.Parse(prev.Manifest.Version)
+	if err != nil {
+		ui.PrintWarning("errors.version_invalid", prev.Manifest.Version)
+		return true
+	}
+	newV, err := semver.Parse(proto.Manifest.Version)
+	if err != nil {
+		ui.PrintError("errors.version_invalid", proto.Manifest.Version)
+		return false
+	}
+	if newV.LessThan(oldV) {
+		ui.PrintError("errors.version_downgrade", newV, oldV)
+		return false
+	}
+
+	report := diff.Compare(prev, proto)
+	if len(report.Changes) > 0 && !proto.Manifest.UpdatedAt.After(prev.Manifest.UpdatedAt) {
+		ui.PrintWarning("errors.updated_at_stale", prev.Manifest.UpdatedAt.Format(time.RFC3339))
+	}
+
+	required := report.Required(oldV)
+	actual := diff.Bump(oldV, newV)
+	if diff.Satisfies(actual, required) {
+		ui.PrintKV("cmd.version_bump", fmt.Sprintf("%s → %s (%s)", oldV, newV, actual))
+		return true
+	}
+
+	suggested := diff.Suggest(oldV, required)
+	var msg string
+	if actual == diff.None {
+		msg = fmt.Sprintf(i18n.T("errors.version_not_bumped"), len(report.Changes), oldV, suggested)
+	} else {
+		msg = fmt.Sprintf(i18n.T("errors.version_bump_insufficient"), oldV, required, newV, actual, suggested)
+	}
+	breaking := report.Breaking()
+	if len(breaking) > 0 || buildStrict {
+		ui.PrintError("common.failure", msg)
+	} else {
+		ui.PrintWarning("common.warning", msg)
+	}
+	for _, c := range breaking {
+		fmt.Printf("   - %s (%s)\n", c.Path, c.Kind)
+	}
+	return len(breaking) == 0 && !buildStrict
+}
+
+// samePath 判断两个路径是否指向同一文件
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
 func init() {
+	buildCmd.Flags().StringVar(&buildAgainst, "against", "", "Previous version to compare against for the version check (default: dist.runly)")
+	buildCmd.Flags().BoolVar(&buildStrict, "strict", false, "Fail instead of warning when the version bump is smaller than recommended")
 	rootCmd.AddCommand(buildCmd)
 }
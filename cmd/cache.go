@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/originbeat-inc/runly-cli/internal/config"
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheExpiredOnly bool
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "💾 Manage the local skill & knowledge response cache",
+}
+
+// cacheLsCmd: 列出缓存记录
+var cacheLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List cached responses",
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.PrintHeader("cmd.cache_header")
+
+		entries, err := cache.NewStore(config.GetCacheDir()).List()
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		if len(entries) == 0 {
+			ui.PrintWarning("cmd.cache_empty")
+			return
+		}
+
+		now := time.Now()
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{i18n.T("cmd.cache_key"), i18n.T("cmd.cache_kind"), "Ref", i18n.T("cmd.cache_request"), i18n.T("cmd.cache_age"), i18n.T("common.status")})
+		table.SetAutoWrapText(false)
+		table.SetBorder(false)
+		for _, e := range entries {
+			status := i18n.T("cmd.cache_valid")
+			if e.Expired(now) {
+				status = i18n.T("cmd.cache_expired")
+			}
+			table.Append([]string{
+				shortCacheKey(e.Key),
+				e.Kind,
+				e.Ref,
+				fmt.Sprintf("%s %s", e.Method, e.Endpoint),
+				now.Sub(e.CreatedAt).Round(time.Second).String(),
+				status,
+			})
+		}
+		table.Render()
+	},
+}
+
+// cacheClearCmd: 清理缓存
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove cached responses",
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := cache.NewStore(config.GetCacheDir()).Clear(cacheExpiredOnly)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		ui.PrintSuccess("common.success")
+		ui.PrintStep("cmd.cache_cleared", removed)
+	},
+}
+
+// shortCacheKey 列表中只显示缓存键前 12 位，手工改动过的缓存文件可能更短
+func shortCacheKey(key string) string {
+	if len(key) > 12 {
+		return key[:12]
+	}
+	return key
+}
+
+func init() {
+	cacheClearCmd.Flags().BoolVar(&cacheExpiredOnly, "expired", false, "Only remove expired entries")
+
+	cacheCmd.AddCommand(cacheLsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
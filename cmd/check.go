@@ -3,14 +3,24 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/internal/ui"
 	"github.com/originbeat-inc/runly-cli/pkg/compiler"
 	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/originbeat-inc/runly-cli/pkg/trust"
 	"github.com/spf13/cobra"
 )
 
+var (
+	checkAllowUntrusted bool
+	checkOffline        bool
+	checkRequire        string
+	checkSigFile        string
+)
+
 var checkCmd = &cobra.Command{
 	Use:   "check [file.runly]",
 	Short: "🔍 Validate protocol and signature",
@@ -24,29 +34,64 @@ var checkCmd = &cobra.Command{
 		// 2. 加载协议资产
 		proto, err := protocol.Load(file)
 		if err != nil {
-			// 提示：读取协议文件失败
-			ui.PrintError("errors.load_fail", err)
+			// 提示：读取协议文件失败 (file:line:col 格式，便于编辑器与 CI 解析)
+			fmt.Printf("❌ [%s]:\n%s\n", i18n.T("common.failure"), protocol.FormatError(err))
 			os.Exit(1)
 		}
 
-		// 3. 7-Domain 逻辑校验
+		// 3. 7-Domain 逻辑校验：一次性收集全部诊断 (规则级别可由 .runlyrc 与内联注释调整)
 		ui.PrintStep("cmd.validate_step")
-		if err := protocol.Validate(proto); err != nil {
-			// 使用 i18n 翻译 "失败" 前缀并输出具体错误
-			fmt.Printf("❌ [%s]: %v\n", i18n.T("common.failure"), err)
+		diags, err := protocol.Check(proto)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		if len(diags) > 0 {
+			// 以 file:line:col: severity[CODE] 格式输出全部诊断，便于编辑器与 CI 解析
+			fmt.Printf("\n%s\n\n", diags.Render())
+			fmt.Printf(i18n.T("cmd.check_summary")+"\n", diags.Count(protocol.SeverityError), diags.Count(protocol.SeverityWarning), diags.Count(protocol.SeverityInfo))
+		}
+		if diags.HasErrors() {
+			fmt.Printf("❌ [%s]\n", i18n.T("common.failure"))
 			os.Exit(1)
 		}
 
-		// 4. 数字签名与完整性验证
+		// 4. 数字签名验证：完整 (内容与内嵌公钥的签名匹配) 与可信 (签名者经信任库或身份服务器确认) 分别判定
 		ui.PrintStep("cmd.signing_step")
-		isValid, err := compiler.VerifyIntegrity(proto)
-		if err != nil || !isValid {
+		verifier := newVerifier(checkOffline)
+		verdict := verifier.Verify(proto)
+		if !verdict.Intact {
 			// 提示：安全签名验证未通过
 			ui.PrintError("errors.sign_verify_fail")
 			os.Exit(1)
 		}
+		ui.PrintKV("cmd.check_intact", "✅ "+i18n.T("cmd.check_intact_ok"))
+		if compiler.IsLegacy(proto) {
+			// 旧版规范化方案签名仍可验证，提示重新 build 迁移到 JCS
+			ui.PrintWarning("cmd.legacy_signature")
+		}
+		if verdict.Trusted {
+			ui.PrintKV("cmd.check_trusted", fmt.Sprintf("✅ %s (%s)", verdict.MeID, verdict.Source))
+		} else {
+			ui.PrintKV("cmd.check_trusted", "❌ "+verdict.Reason.Error())
+			if verdict.Revoked() {
+				// 签名晚于密钥吊销或轮换时间：--allow-untrusted 也不放行
+				ui.PrintError("errors.signature_revoked")
+				os.Exit(1)
+			}
+			if !checkAllowUntrusted {
+				ui.PrintError("errors.untrusted_signer")
+				os.Exit(1)
+			}
+			ui.PrintWarning("errors.untrusted_signer")
+		}
+
+		// 5. 多方签名与签名策略：伪造或与内容不符的签名直接失败，策略只统计有效且可信的签名者
+		if !checkSignatures(file, proto, verifier) {
+			os.Exit(1)
+		}
 
-		// 5. 最终反馈
+		// 6. 最终反馈
 		ui.PrintSuccess("common.success")
 
 		// 额外打印资产基本信息，增加专业感
@@ -56,6 +101,99 @@ var checkCmd = &cobra.Command{
 	},
 }
 
+// checkSignatures 验证内嵌与分离签名文件中的多方签名，并按 --require 或 .runlyrc 的签名策略判定
+func checkSignatures(file string, proto *protocol.RunlyProtocol, verifier *trust.Verifier) bool {
+	policy := trust.Policy{}
+	if checkRequire != "" {
+		p, err := trust.ParsePolicy(checkRequire)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			return false
+		}
+		policy = p
+	} else if rc, err := protocol.LoadRuleConfig(file); err == nil {
+		policy = trust.Policy(rc.Signatures)
+	}
+
+	detached, ok := loadDetachedSignatures(file, proto)
+	if !ok {
+		return false
+	}
+	verdicts, err := verifier.VerifySignatures(proto, detached)
+	if err != nil {
+		ui.PrintError("common.failure", err)
+		return false
+	}
+	if len(proto.Security.Signatures)+len(detached) == 0 && len(policy) == 0 {
+		return true
+	}
+
+	ui.PrintStep("cmd.cosign_step")
+	printSignatures(verdicts)
+	for _, v := range verdicts {
+		if !v.Valid {
+			ui.PrintError("errors.cosign_rejected")
+			return false
+		}
+	}
+	if unmet := policy.Unmet(verdicts); len(unmet) > 0 {
+		ui.PrintError("errors.signature_policy_unmet", strings.Join(unmet, ", "))
+		return false
+	}
+	return true
+}
+
+// printSignatures 逐行输出多方签名的验证结果
+func printSignatures(verdicts []*trust.SignatureVerdict) {
+	for _, v := range verdicts {
+		mark := "✅"
+		detail := v.Source
+		if !v.Valid || !v.Trusted {
+			mark, detail = "❌", v.Reason.Error()
+		}
+		at := ""
+		if !v.Signature.SignedAt.IsZero() {
+			at = " " + v.Signature.SignedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("   %s %-12s %s [%s]%s — %s\n", mark, v.Signature.Role, v.Signature.SignerID, trust.Short(v.Signature.KeyID), at, detail)
+	}
+}
+
+// loadDetachedSignatures 读取分离签名文件：--sig 指定的文件必须与资产内容匹配，
+// 自动发现的 <file>.sig 已过期 (资产重新构建) 时仅警告并忽略
+func loadDetachedSignatures(file string, proto *protocol.RunlyProtocol) ([]protocol.Signature, bool) {
+	path := checkSigFile
+	if path == "" {
+		path = compiler.DetachedPath(file)
+		if _, err := os.Stat(path); err != nil {
+			return nil, true
+		}
+	}
+
+	d, err := compiler.LoadDetached(path)
+	if err != nil {
+		ui.PrintError("errors.load_fail", err)
+		return nil, false
+	}
+	digest, err := compiler.Digest(proto)
+	if err == nil {
+		err = d.Matches(digest)
+	}
+	if err != nil {
+		if checkSigFile != "" {
+			ui.PrintError("common.failure", err)
+			return nil, false
+		}
+		ui.PrintWarning("common.warning", err)
+		return nil, true
+	}
+	return d.Signatures, true
+}
+
 func init() {
+	checkCmd.Flags().BoolVar(&checkAllowUntrusted, "allow-untrusted", false, "Only warn when the signer is not trusted")
+	checkCmd.Flags().BoolVar(&checkOffline, "offline", false, "Use only the local trust store, never query the Me server")
+	checkCmd.Flags().StringVar(&checkRequire, "require", "", "Signature policy, e.g. \"creator,reviewer=1\" (overrides .runlyrc)")
+	checkCmd.Flags().StringVar(&checkSigFile, "sig", "", "Detached signature file (default: <file>.sig when present)")
 	rootCmd.AddCommand(checkCmd)
 }
@@ -25,6 +25,7 @@ var setCmd = &cobra.Command{
 		token, _ := cmd.Flags().GetString("token")
 		hub, _ := cmd.Flags().GetString("hub")
 		me, _ := cmd.Flags().GetString("me")
+		secretCmd, _ := cmd.Flags().GetString("secret-cmd")
 
 		modified := false
 
@@ -40,6 +41,10 @@ var setCmd = &cobra.Command{
 			profile.MeServer = me
 			modified = true
 		}
+		if secretCmd != "" {
+			profile.SecretCmd = secretCmd
+			modified = true
+		}
 
 		if modified {
 			cfg.Profiles[cfg.ActiveProfile] = profile
@@ -96,6 +101,7 @@ func init() {
 	setCmd.Flags().String("token", "", i18n.T("cmd.config_flag_token"))
 	setCmd.Flags().String("hub", "", i18n.T("cmd.config_flag_hub"))
 	setCmd.Flags().String("me", "", i18n.T("cmd.config_flag_me"))
+	setCmd.Flags().String("secret-cmd", "", i18n.T("cmd.config_flag_secret_cmd"))
 
 	configCmd.AddCommand(setCmd)
 	configCmd.AddCommand(setupCmd)
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/coverage"
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var (
+	coverageTraces    []string
+	coverageFormat    string
+	coverageThreshold float64
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage [file.runly]",
+	Short: "📈 Report topology coverage from recorded run traces",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := args[0]
+
+		// 1. 加载协议资产
+		proto, err := protocol.Load(file)
+		if err != nil {
+			ui.PrintError("errors.load_fail", protocol.FormatError(err))
+			os.Exit(1)
+		}
+
+		// 2. 收集轨迹文件 (支持目录，自动展开其中的 *.json)
+		paths, err := expandTracePaths(coverageTraces)
+		if err != nil || len(paths) == 0 {
+			ui.PrintError("errors.trace_missing")
+			os.Exit(1)
+		}
+
+		var traces []*executor.Trace
+		for _, p := range paths {
+			t, err := executor.LoadTrace(p)
+			if err != nil {
+				ui.PrintError("common.failure", err)
+				os.Exit(1)
+			}
+			traces = append(traces, t)
+		}
+
+		// 3. 计算覆盖率并输出
+		report := coverage.Compute(proto, traces)
+		if report.Skipped > 0 {
+			// ⚠️ 已忽略 %d 份不属于 %s@%s 的轨迹
+			ui.PrintWarning("cmd.coverage_traces_skipped", report.Skipped, proto.Manifest.URN, proto.Manifest.Version)
+		}
+		if coverageFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printCoverageText(report)
+		}
+
+		// 4. 阈值门禁 (用于 CI)
+		if coverageThreshold > 0 {
+			if failed := report.Below(coverageThreshold); len(failed) > 0 {
+				ui.PrintError("errors.coverage_below_threshold", strings.Join(failed, ", "), coverageThreshold)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// printCoverageText 以表格形式展示覆盖率
+func printCoverageText(r *coverage.Report) {
+	ui.PrintHeader("cmd.coverage_header")
+	ui.PrintKV("cmd.coverage_traces", r.Traces)
+	fmt.Println()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"", i18n.T("cmd.coverage_covered"), i18n.T("cmd.coverage_total"), "%"})
+	table.SetBorder(false)
+	for _, row := range []struct {
+		key string
+		m   coverage.Metric
+	}{
+		{"cmd.coverage_nodes", r.Nodes},
+		{"cmd.coverage_edges", r.Edges},
+		{"cmd.coverage_rules", r.Rules},
+	} {
+		table.Append([]string{
+			i18n.T(row.key),
+			fmt.Sprintf("%d", row.m.Covered),
+			fmt.Sprintf("%d", row.m.Total),
+			fmt.Sprintf("%.1f", row.m.Percent),
+		})
+	}
+	table.Render()
+
+	if len(r.UncoveredNodes) > 0 {
+		fmt.Printf("\n%s:\n", i18n.T("cmd.coverage_uncovered_nodes"))
+		for _, id := range r.UncoveredNodes {
+			fmt.Printf("   - %s\n", id)
+		}
+	}
+	if len(r.UncoveredEdges) > 0 {
+		fmt.Printf("\n%s:\n", i18n.T("cmd.coverage_uncovered_edges"))
+		for _, e := range r.UncoveredEdges {
+			fmt.Printf("   - %s --%s--> %s\n", e.From, e.Kind, e.To)
+		}
+	}
+	if len(r.UncoveredRules) > 0 {
+		fmt.Printf("\n%s:\n", i18n.T("cmd.coverage_uncovered_rules"))
+		for _, rule := range r.UncoveredRules {
+			fmt.Printf("   - %s#%d [%s] -> %s\n", rule.NodeID, rule.Index+1, rule.Condition, rule.Next)
+		}
+	}
+}
+
+// expandTracePaths 将目录参数展开为其中的轨迹文件列表
+func expandTracePaths(inputs []string) ([]string, error) {
+	var paths []string
+	for _, in := range inputs {
+		info, err := os.Stat(in)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, in)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(in, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+func init() {
+	coverageCmd.Flags().StringArrayVarP(&coverageTraces, "trace", "t", nil, "Trace file or directory recorded by 'run --trace' (repeatable)")
+	coverageCmd.Flags().StringVarP(&coverageFormat, "format", "f", "text", "Output format: text | json")
+	coverageCmd.Flags().Float64Var(&coverageThreshold, "threshold", 0, "Fail when node, edge or rule coverage is below this percentage")
+	rootCmd.AddCommand(coverageCmd)
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/diff"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/originbeat-inc/runly-cli/pkg/textdiff"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat         string
+	diffFailOnBreaking bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [old.runly] [new.runly]",
+	Short: "🆚 Compare two versions of an SOP at the protocol level",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		// 1. 加载新旧两个版本
+		var protos [2]*protocol.RunlyProtocol
+		for i, file := range args {
+			proto, err := protocol.Load(file)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ [%s]:\n%s\n", i18n.T("common.failure"), protocol.FormatError(err))
+				os.Exit(1)
+			}
+			protos[i] = proto
+		}
+
+		// 2. 协议级比较
+		report := diff.Compare(protos[0], protos[1])
+
+		// 3. 按格式输出
+		if diffFormat == "json" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			fmt.Println(string(data))
+		} else {
+			printDiffText(report)
+		}
+
+		// 4. 存在破坏性变更时可以非零状态退出 (用于 CI)
+		if diffFailOnBreaking && len(report.Breaking()) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// printDiffText 按区块分组展示变更，提示词给出词级差异
+func printDiffText(r *diff.Report) {
+	ui.PrintHeader("cmd.diff_header")
+	ui.PrintKV("cmd.diff_versions", r.OldVersion+" → "+r.NewVersion)
+
+	if len(r.Changes) == 0 {
+		fmt.Println()
+		ui.PrintSuccess("cmd.diff_identical")
+		return
+	}
+
+	section := ""
+	for _, c := range r.Changes {
+		if c.Section != section {
+			section = c.Section
+			fmt.Printf("\n%s\n", pterm.Bold.Sprint("["+section+"]"))
+		}
+
+		var line string
+		switch c.Kind {
+		case diff.Added:
+			line = pterm.Green("+ "+c.Path) + valueSuffix(c.New)
+		case diff.Removed:
+			line = pterm.Red("- "+c.Path) + valueSuffix(c.Old)
+		default:
+			line = pterm.Yellow("~ " + c.Path)
+			if c.Words == nil {
+				line += ": " + c.Old + " → " + c.New
+			}
+		}
+		fmt.Printf("   %s %s\n", impactTag(c.Impact), line)
+		if c.Words != nil {
+			fmt.Printf("        %s\n", colorWords(c.Words))
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf(i18n.T("cmd.diff_summary")+"\n", len(r.Changes), len(r.Breaking()))
+	ui.PrintKV("cmd.diff_bump", strings.ToUpper(string(r.Impact)))
+}
+
+func valueSuffix(v string) string {
+	if v == "" {
+		return ""
+	}
+	return pterm.Gray(" (" + v + ")")
+}
+
+// impactTag 变更级别标签，破坏性变更醒目标红
+func impactTag(i diff.Impact) string {
+	tag := fmt.Sprintf("%-7s", "["+strings.ToUpper(string(i))+"]")
+	switch i {
+	case diff.Major:
+		return pterm.LightRed(tag)
+	case diff.Minor:
+		return pterm.LightYellow(tag)
+	}
+	return pterm.Gray(tag)
+}
+
+// colorWords 词级差异着色：删除为红色删除线，新增为绿色
+func colorWords(ops []textdiff.Op) string {
+	var b strings.Builder
+	for _, op := range ops {
+		switch op.Kind {
+		case textdiff.Delete:
+			b.WriteString(pterm.NewStyle(pterm.FgRed, pterm.Strikethrough).Sprint(op.Text))
+		case textdiff.Insert:
+			b.WriteString(pterm.NewStyle(pterm.FgGreen, pterm.Bold).Sprint(op.Text))
+		default:
+			b.WriteString(op.Text)
+		}
+	}
+	return b.String()
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "Output format: text | json")
+	diffCmd.Flags().BoolVar(&diffFailOnBreaking, "fail-on-breaking", false, "Exit with a non-zero status when breaking changes are found")
+	rootCmd.AddCommand(diffCmd)
+}
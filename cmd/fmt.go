@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/formatter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fmtCheck bool
+	fmtWrite bool
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [file.runly...]",
+	Short: "🧹 Format .runly files into the canonical layout",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		unformatted := 0
+		for _, file := range args {
+			// 1. 读取并格式化 (基于 YAML 语法树，保留注释)
+			data, err := os.ReadFile(file)
+			if err != nil {
+				ui.PrintError("errors.load_fail", err)
+				os.Exit(1)
+			}
+			out, err := formatter.Format(data)
+			if err != nil {
+				ui.PrintError("common.failure", file, ": ", err)
+				os.Exit(1)
+			}
+			changed := !bytes.Equal(data, out)
+
+			// 2. 按模式输出：--check 仅报告，-w 原地改写，默认输出到 stdout
+			switch {
+			case fmtCheck:
+				if changed {
+					unformatted++
+					ui.PrintWarning("cmd.fmt_unformatted", file)
+				}
+			case fmtWrite:
+				if !changed {
+					continue
+				}
+				info, err := os.Stat(file)
+				if err != nil {
+					ui.PrintError("errors.load_fail", err)
+					os.Exit(1)
+				}
+				if err := os.WriteFile(file, out, info.Mode().Perm()); err != nil {
+					ui.PrintError("common.failure", err)
+					os.Exit(1)
+				}
+				ui.PrintStep("cmd.fmt_rewritten", file)
+			default:
+				os.Stdout.Write(out)
+			}
+		}
+
+		// 3. --check 存在未格式化文件时以非零状态退出 (用于 CI)
+		if fmtCheck {
+			if unformatted > 0 {
+				os.Exit(1)
+			}
+			ui.PrintSuccess("cmd.fmt_clean")
+		}
+	},
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "Report files that are not formatted and exit non-zero")
+	fmtCmd.Flags().BoolVarP(&fmtWrite, "write", "w", false, "Rewrite files in place")
+	rootCmd.AddCommand(fmtCmd)
+}
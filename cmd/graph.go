@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+	"github.com/originbeat-inc/runly-cli/pkg/graph"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var (
+	graphFormat string
+	graphOutput string
+	graphTraces []string
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [file.runly]",
+	Short: "🗺️  Export the SOP topology as a Mermaid, Graphviz DOT or SVG diagram",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// 1. 加载协议资产
+		proto, err := protocol.Load(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ [%s]:\n%s\n", i18n.T("common.failure"), protocol.FormatError(err))
+			os.Exit(1)
+		}
+
+		// 2. 构建拓扑图，可选叠加运行轨迹以标记实际执行路径
+		g := graph.Build(proto)
+		if len(graphTraces) > 0 {
+			paths, err := expandTracePaths(graphTraces)
+			if err != nil || len(paths) == 0 {
+				ui.PrintError("errors.trace_missing")
+				os.Exit(1)
+			}
+			var traces []*executor.Trace
+			for _, p := range paths {
+				t, err := executor.LoadTrace(p)
+				if err != nil {
+					ui.PrintError("common.failure", err)
+					os.Exit(1)
+				}
+				traces = append(traces, t)
+			}
+			g.ApplyTraces(traces)
+		}
+
+		// 3. 按格式渲染
+		var out string
+		switch graphFormat {
+		case "mermaid":
+			out = graph.Mermaid(g)
+		case "dot":
+			out = graph.DOT(g)
+		case "svg":
+			out = graph.SVG(g)
+		default:
+			ui.PrintError("errors.graph_format_invalid", graphFormat)
+			os.Exit(1)
+		}
+
+		// 4. 输出到文件或 stdout
+		if graphOutput == "" {
+			fmt.Print(out)
+			return
+		}
+		if err := os.WriteFile(graphOutput, []byte(out), 0644); err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📄 %s: %s\n", i18n.T("common.output"), graphOutput)
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringVarP(&graphFormat, "format", "f", "mermaid", "Output format: mermaid | dot | svg")
+	graphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Write the diagram to a file instead of stdout")
+	graphCmd.Flags().StringArrayVarP(&graphTraces, "trace", "t", nil, "Overlay a trace file or directory recorded by 'run --trace' (repeatable)")
+	rootCmd.AddCommand(graphCmd)
+}
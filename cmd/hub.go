@@ -4,18 +4,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/originbeat-inc/runly-cli/internal/config"
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/internal/ui"
-	"github.com/originbeat-inc/runly-cli/pkg/compiler"
 	"github.com/originbeat-inc/runly-cli/pkg/executor/adapter"
 	"github.com/originbeat-inc/runly-cli/pkg/protocol"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+var hubAllowUntrusted bool
+
 var hubCmd = &cobra.Command{
 	Use:   "hub",
 	Short: "🌐 Explore and manage assets on Runly Hub",
@@ -147,13 +149,30 @@ var hubPullCmd = &cobra.Command{
 			return
 		}
 
-		// 执行多语言指纹校验步骤
+		// 执行多语言指纹校验步骤：内容完整且签名者可信才写入本地
 		ui.PrintStep("cmd.signing_step")
-		isValid, err := compiler.VerifyIntegrity(&proto)
-		if err != nil || !isValid {
+		// Hub 记录的发布时间不由签名者控制，作为可信时间判断签名是否早于密钥吊销
+		verifier := newVerifier(false)
+		if s, ok := data["published_at"].(string); ok {
+			verifier.TrustedTime, _ = time.Parse(time.RFC3339, s)
+		}
+		verdict := verifier.Verify(&proto)
+		if !verdict.Intact {
 			ui.PrintError("errors.sign_verify_fail")
 			return
 		}
+		if !verdict.Trusted {
+			fmt.Printf("   ❌ %s\n", verdict.Reason)
+			if verdict.Revoked() {
+				ui.PrintError("errors.signature_revoked")
+				return
+			}
+			if !hubAllowUntrusted {
+				ui.PrintError("errors.untrusted_signer")
+				return
+			}
+			ui.PrintWarning("errors.untrusted_signer")
+		}
 
 		fileName := fmt.Sprintf("%s.runly", proto.Manifest.URN)
 		savePath := filepath.Clean(fileName)
@@ -173,6 +192,7 @@ var hubPullCmd = &cobra.Command{
 func init() {
 	hubCmd.AddCommand(hubTemplatesCmd)
 	hubCmd.AddCommand(hubSearchCmd)
+	hubPullCmd.Flags().BoolVar(&hubAllowUntrusted, "allow-untrusted", false, "Save the asset even if the signer is not trusted")
 	hubCmd.AddCommand(hubPullCmd)
 	rootCmd.AddCommand(hubCmd)
 }
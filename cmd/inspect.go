@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+	"github.com/originbeat-inc/runly-cli/pkg/graph"
+	"github.com/originbeat-inc/runly-cli/pkg/inspect"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var inspectNode string
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [file.runly]",
+	Short: "🔬 Show the topology as a tree and inspect node data flow",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// 1. 加载协议资产
+		proto, err := protocol.Load(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ [%s]:\n%s\n", i18n.T("common.failure"), protocol.FormatError(err))
+			os.Exit(1)
+		}
+
+		// 2. 推导各节点读写的变量
+		report := inspect.Analyze(proto)
+
+		// 3. 指定 --node 时展示单个节点详情，否则从 start_at 展开拓扑树
+		if inspectNode == "" {
+			printTopologyTree(proto, report)
+			return
+		}
+		n := report.Node(inspectNode)
+		if n == nil {
+			ui.PrintError("errors.inspect_node_missing", inspectNode)
+			if c := protocol.Closest(inspectNode, report.IDs()); c != "" {
+				fmt.Printf("   💡 %s\n", fmt.Sprintf(i18n.T("errors.hint_did_you_mean"), c))
+			}
+			os.Exit(1)
+		}
+		printNodeDetail(proto, report, n)
+	},
+}
+
+// printTopologyTree 从起始节点深度优先展开拓扑，已展开过的节点只显示引用
+func printTopologyTree(proto *protocol.RunlyProtocol, r *inspect.Report) {
+	ui.PrintHeader("cmd.inspect_header")
+
+	title := proto.Manifest.Title
+	if proto.Manifest.URN != "" {
+		title += " (" + proto.Manifest.URN + ")"
+	}
+	root := pterm.TreeNode{Text: title}
+
+	visited := make(map[string]bool)
+	root.Children = append(root.Children, nodeTree(r, "▶ ", proto.Topology.StartAt, visited))
+
+	// 无法从起始节点到达的节点单独列出
+	for _, n := range r.Nodes {
+		if !visited[n.Spec.ID] {
+			root.Children = append(root.Children, nodeTree(r, "🏝️ ", n.Spec.ID, visited))
+		}
+	}
+
+	_ = pterm.DefaultTree.WithRoot(root).Render()
+}
+
+// nodeTree 生成单个节点的子树：资源引用、读写变量与出口边 (出口边下挂目标节点)
+func nodeTree(r *inspect.Report, prefix, id string, visited map[string]bool) pterm.TreeNode {
+	if id == graph.Terminate {
+		return pterm.TreeNode{Text: prefix + "⏹ " + id}
+	}
+	n := r.Node(id)
+	if n == nil {
+		return pterm.TreeNode{Text: prefix + pterm.Red(id+" "+i18n.T("cmd.inspect_undefined"))}
+	}
+
+	text := prefix + pterm.Bold.Sprint(id) + " " + pterm.FgCyan.Sprint("["+n.Spec.Type+"]")
+	if visited[id] {
+		return pterm.TreeNode{Text: text + " " + pterm.Gray(i18n.T("cmd.inspect_seen"))}
+	}
+	visited[id] = true
+
+	t := pterm.TreeNode{Text: text}
+	info := func(key string, values []string) {
+		if len(values) > 0 {
+			t.Children = append(t.Children, pterm.TreeNode{Text: pterm.Gray(i18n.T(key)+": ") + strings.Join(values, ", ")})
+		}
+	}
+	if ref, _ := n.Spec.Config["skill_ref"].(string); ref != "" {
+		info("cmd.inspect_skill", []string{ref})
+	}
+	if ref, _ := n.Spec.Config["knowledge_ref"].(string); ref != "" {
+		info("cmd.inspect_knowledge", []string{ref})
+	}
+	info("cmd.inspect_reads", n.Reads)
+	info("cmd.inspect_writes", n.Writes)
+
+	for _, e := range n.Edges {
+		label := e.Label
+		if label == "" {
+			label = e.Kind
+		}
+		if e.Kind == executor.EdgeFailure {
+			label = pterm.Red(label)
+		}
+		t.Children = append(t.Children, nodeTree(r, label+" → ", e.To, visited))
+	}
+	return t
+}
+
+// printNodeDetail 展示节点完整配置、引用的资源以及上下游数据依赖
+func printNodeDetail(proto *protocol.RunlyProtocol, r *inspect.Report, n *inspect.Node) {
+	ui.PrintHeader("cmd.inspect_node_header")
+	ui.PrintKV("cmd.inspect_node", n.Spec.ID)
+	ui.PrintKV("cmd.inspect_type", n.Spec.Type)
+	if n.Spec.Pos.IsValid() {
+		ui.PrintKV("cmd.inspect_position", fmt.Sprintf("%s:%d:%d", n.Spec.Pos.File, n.Spec.Pos.Line, n.Spec.Pos.Column))
+	}
+
+	// 1. 节点及其引用资源的完整声明
+	printYAMLSection("cmd.inspect_config", n.Spec)
+	if n.Skill != nil {
+		printYAMLSection("cmd.inspect_skill", n.Skill)
+	}
+	if n.Knowledge != nil {
+		printYAMLSection("cmd.inspect_knowledge", n.Knowledge)
+	}
+
+	// 2. 数据依赖：输入参数、上游步骤与下游消费者
+	var inputs []string
+	for _, name := range n.Inputs() {
+		inputs = append(inputs, describeInput(proto, name))
+	}
+	printListSection("cmd.inspect_reads", n.Reads)
+	printListSection("cmd.inspect_writes", n.Writes)
+	printListSection("cmd.inspect_inputs", inputs)
+	printListSection("cmd.inspect_upstream", r.Producers(n.Spec.ID))
+	printListSection("cmd.inspect_consumers", r.Consumers(n.Spec.ID))
+
+	var edges []string
+	for _, e := range n.Edges {
+		label := e.Label
+		if label == "" {
+			label = e.Kind
+		}
+		edges = append(edges, label+" → "+e.To)
+	}
+	printListSection("cmd.inspect_edges", edges)
+}
+
+// describeInput 输入参数的类型与必填信息，未在 Dictionary 中声明时给出标记
+func describeInput(proto *protocol.RunlyProtocol, name string) string {
+	for _, in := range proto.Dictionary.Inputs {
+		if in.Name != name {
+			continue
+		}
+		desc := name + " (" + in.Type
+		if in.Required {
+			desc += ", required"
+		}
+		return desc + ")"
+	}
+	return name + " " + pterm.Red(i18n.T("cmd.inspect_undefined"))
+}
+
+func printYAMLSection(key string, v interface{}) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return
+	}
+	fmt.Printf("\n%s:\n", i18n.T(key))
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+}
+
+func printListSection(key string, items []string) {
+	fmt.Printf("\n%s:\n", i18n.T(key))
+	if len(items) == 0 {
+		fmt.Printf("   %s\n", pterm.Gray(i18n.T("cmd.inspect_none")))
+		return
+	}
+	for _, item := range items {
+		fmt.Printf("   - %s\n", item)
+	}
+}
+
+func init() {
+	inspectCmd.Flags().StringVarP(&inspectNode, "node", "n", "", "Show the full config and data dependencies of a single node")
+	rootCmd.AddCommand(inspectCmd)
+}
@@ -6,12 +6,24 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/originbeat-inc/runly-cli/internal/config"
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/crypto"
 	"github.com/originbeat-inc/runly-cli/pkg/executor/adapter"
+	"github.com/originbeat-inc/runly-cli/pkg/keystore"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	keysForce    bool
+	keysLocal    bool
+	keysRevokeAt string
 )
 
 var keysCmd = &cobra.Command{
@@ -19,10 +31,10 @@ var keysCmd = &cobra.Command{
 	Short: "🔑 Identity & Runly Me Management",
 }
 
-// generateCmd：云端优先逻辑
+// generateCmd：默认在本地生成密钥对，仅向身份服务器登记公钥；--local=false 沿用云端同步逻辑
 var generateCmd = &cobra.Command{
 	Use:   "generate [username]",
-	Short: "Sync or create identity keys with Runly Cloud",
+	Short: "Create an identity keypair locally and register its public key",
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		ui.PrintHeader("cmd.keys_header")
@@ -33,73 +45,184 @@ var generateCmd = &cobra.Command{
 			ui.PrintError("common.failure", err)
 			os.Exit(1)
 		}
-		profile := cfg.GetActive()
+		if !keysLocal {
+			syncCloudKeys(cfg, args)
+			return
+		}
+
+		// 2. 已有身份时不覆盖，避免丢失已签名资产对应的私钥
+		if hasSigningKey(cfg) {
+			ui.PrintWarning("cmd.keys_exists")
+			printCurrentIdentity(cfg)
+			return
+		}
 
-		// 2. 初始化 Client 并强制切换到 MeServer
-		client := adapter.NewClient().SetToMeServer()
-		ui.PrintStep("executor.syncing")
+		// 3. 本地生成密钥对，私钥种子不离开本机
+		ui.PrintStep("cmd.keys_generating")
+		pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+		pubHex := hex.EncodeToString(pub)
+		privSeedHex := hex.EncodeToString(priv.Seed())
 
-		// 3. 尝试从云端拉取
-		cloudData, err := client.Post("/v1/me/keys/pull", nil)
+		// 4. 以挑战签名证明私钥持有并登记公钥；服务器不可用时只保存待登记的密钥 (MeID 留空)，
+		// 登记完成前不能签名，稍后执行 keys register 取得服务器分配的 MeID
+		meID, err := registerPublicKey(keysUsername(args), pubHex, privSeedHex)
+		if err == nil && meID == "" {
+			err = fmt.Errorf(i18n.T("errors.key_register_no_meid"))
+		}
+		if err != nil {
+			meID = ""
+			ui.PrintWarning("cmd.keys_register_pending", err)
+		}
 
+		saveKeys(cfg, meID, pubHex, privSeedHex)
 		if err == nil {
-			// --- 情况 A: 云端已有密钥 ---
-			cloudPub, _ := cloudData["public_key"].(string)
-			cloudPriv, _ := cloudData["secret_key"].(string)
-			cloudMeID, _ := cloudData["me_id"].(string)
-
-			// 一致性校验：如果本地已有密钥且与云端不符，报错拦截
-			if profile.PublicKey != "" && profile.PublicKey != cloudPub {
-				ui.PrintWarning("common.warning", "Identity Mismatch!")
-				fmt.Printf("   Local Public Key: %s\n", profile.PublicKey)
-				fmt.Printf("   Cloud Public Key: %s\n", cloudPub)
-				ui.PrintError("errors.auth_failed", "Keys mismatch between local and cloud.")
+			ui.PrintSuccess("cmd.keys_local_success")
+		}
+		printCurrentIdentity(cfg)
+	},
+}
+
+// registerCmd：向身份服务器 (重新) 登记本地公钥
+var registerCmd = &cobra.Command{
+	Use:   "register [username]",
+	Short: "Register the local public key with the Me server (proof of possession)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.PrintHeader("cmd.keys_header")
+		cfg, _ := config.LoadConfig()
+		if !hasSigningKey(cfg) {
+			ui.PrintError("errors.no_key")
+			os.Exit(1)
+		}
+		seed := signingKeyOrExit(cfg)
+		profile := cfg.GetActive()
+
+		meID, err := registerPublicKey(keysUsername(args), profile.PublicKey, seed)
+		if err == nil && meID == "" && profile.MeID == "" {
+			err = fmt.Errorf(i18n.T("errors.key_register_no_meid"))
+		}
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		if meID != "" && meID != profile.MeID {
+			if err := updateKeyMeID(cfg, meID); err != nil {
+				ui.PrintError("errors.key_save_fail", err)
 				os.Exit(1)
 			}
+		}
+		ui.PrintSuccess("cmd.keys_registered")
+		printCurrentIdentity(cfg)
+	},
+}
 
-			// 保存拉取的密钥到本地
-			saveKeys(cfg, cloudMeID, cloudPub, cloudPriv)
-			ui.PrintSuccess("common.success")
-			ui.PrintStep("Identity synced from Cloud Console.")
+// syncCloudKeys 云端同步逻辑：拉取云端密钥，云端没有时生成并上传 (含私钥，用于 Web 可视化编辑签名)
+func syncCloudKeys(cfg *config.CLIConfig, args []string) {
+	profile := cfg.GetActive()
 
-		} else {
-			// --- 情况 B: 云端无密钥，执行本地生成并同步 ---
-			ui.PrintStep("No identity found on cloud. Generating new keypair...")
+	// 2. 初始化 Client 并强制切换到 MeServer
+	client := adapter.NewClient().SetToMeServer()
+	ui.PrintStep("executor.syncing")
 
-			pub, priv, _ := ed25519.GenerateKey(rand.Reader)
-			pubHex := hex.EncodeToString(pub)
-			privSeedHex := hex.EncodeToString(priv.Seed())
+	// 3. 尝试从云端拉取
+	cloudData, err := client.Post("/v1/me/keys/pull", nil)
 
-			username := "anonymous"
-			if len(args) > 0 {
-				username = args[0]
-			}
+	if err == nil {
+		// --- 情况 A: 云端已有密钥 ---
+		cloudPub, _ := cloudData["public_key"].(string)
+		cloudPriv, _ := cloudData["secret_key"].(string)
+		cloudMeID, _ := cloudData["me_id"].(string)
 
-			// 构造同步载荷 (包含私钥用于 Web 可视化编辑签名)
-			payload := map[string]interface{}{
-				"username":   username,
-				"public_key": pubHex,
-				"secret_key": privSeedHex,
-			}
+		// 一致性校验：如果本地已有密钥且与云端不符，报错拦截
+		if profile.PublicKey != "" && profile.PublicKey != cloudPub {
+			ui.PrintWarning("common.warning", "Identity Mismatch!")
+			fmt.Printf("   Local Public Key: %s\n", profile.PublicKey)
+			fmt.Printf("   Cloud Public Key: %s\n", cloudPub)
+			ui.PrintError("errors.auth_failed", "Keys mismatch between local and cloud.")
+			os.Exit(1)
+		}
 
-			resp, err := client.Post("/v1/me/keys/sync", payload)
-			if err != nil {
-				ui.PrintError("common.failure", err)
-				os.Exit(1)
-			}
+		// 保存拉取的密钥到本地
+		saveKeys(cfg, cloudMeID, cloudPub, cloudPriv)
+		ui.PrintSuccess("common.success")
+		ui.PrintStep("Identity synced from Cloud Console.")
 
-			meID, _ := resp["me_id"].(string)
-			if meID == "" {
-				meID = "me_0x" + pubHex[:12]
-			}
+	} else {
+		// --- 情况 B: 云端无密钥，执行本地生成并同步 ---
+		ui.PrintStep("No identity found on cloud. Generating new keypair...")
 
-			saveKeys(cfg, meID, pubHex, privSeedHex)
-			ui.PrintSuccess("cmd.key_gen_success")
+		pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+		pubHex := hex.EncodeToString(pub)
+		privSeedHex := hex.EncodeToString(priv.Seed())
+
+		// 构造同步载荷 (包含私钥用于 Web 可视化编辑签名)
+		payload := map[string]interface{}{
+			"username":   keysUsername(args),
+			"public_key": pubHex,
+			"secret_key": privSeedHex,
 		}
 
-		// 4. 打印最终身份状态
-		printCurrentIdentity(cfg)
-	},
+		resp, err := client.Post("/v1/me/keys/sync", payload)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		meID, _ := resp["me_id"].(string)
+		if meID == "" {
+			meID = "me_0x" + pubHex[:12]
+		}
+
+		saveKeys(cfg, meID, pubHex, privSeedHex)
+		ui.PrintSuccess("cmd.key_gen_success")
+	}
+
+	// 4. 打印最终身份状态
+	printCurrentIdentity(cfg)
+}
+
+// registerPublicKey 挑战-应答登记公钥：申请挑战、以私钥签名挑战作为持有证明，返回服务器分配的 MeID
+func registerPublicKey(username, pubHex, privSeedHex string) (string, error) {
+	client := adapter.NewClient()
+	ui.PrintStep("common.syncing")
+
+	ch, err := client.RequestKeyChallenge(pubHex)
+	if err != nil {
+		return "", err
+	}
+	if !ch.ExpiresAt.IsZero() && time.Now().After(ch.ExpiresAt) {
+		return "", fmt.Errorf(i18n.T("errors.key_challenge_expired"))
+	}
+	proof, err := crypto.Sign(privSeedHex, ch.ProofMessage(pubHex))
+	if err != nil {
+		return "", err
+	}
+	return client.RegisterKey(username, pubHex, ch, proof)
+}
+
+// updateKeyMeID 更新当前 Profile 与密钥文件中记录的 MeID (私钥密文不变)
+func updateKeyMeID(cfg *config.CLIConfig, meID string) error {
+	path := config.GetKeyPath(cfg.ActiveProfile)
+	f, err := keystore.Load(path)
+	if err != nil {
+		return err
+	}
+	f.MeID = meID
+	if err := f.Save(path); err != nil {
+		return err
+	}
+
+	p := cfg.Profiles[cfg.ActiveProfile]
+	p.MeID = meID
+	cfg.Profiles[cfg.ActiveProfile] = p
+	return cfg.SaveConfig()
+}
+
+func keysUsername(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	return "anonymous"
 }
 
 // showCmd：显示本地身份 (100% 还原你的 Emoji 格式)
@@ -110,7 +233,7 @@ var showCmd = &cobra.Command{
 		cfg, _ := config.LoadConfig()
 		profile := cfg.GetActive()
 
-		if profile.SecretKey == "" {
+		if !hasSigningKey(cfg) {
 			ui.PrintError("errors.no_key")
 			return
 		}
@@ -118,33 +241,421 @@ var showCmd = &cobra.Command{
 		ui.PrintHeader("cmd.keys_header")
 
 		fmt.Printf("📊 %s: %s\n", i18n.T("common.status"), cfg.ActiveProfile)
-		fmt.Printf("👤 MeID:    %s\n", profile.MeID)
+		meID := profile.MeID
+		if meID == "" {
+			meID = i18n.T("cmd.keys_meid_pending")
+		}
+		fmt.Printf("👤 MeID:    %s\n", meID)
 		fmt.Printf("🔑 %s:  %s\n", i18n.T("manifest.pub_key_label"), profile.PublicKey)
 		fmt.Printf("🌐 %s:  %s\n", i18n.T("manifest.server_label"), profile.MeServer)
+		if profile.SecretKey != "" {
+			// 明文私钥仍在配置中，提示执行 keys migrate
+			ui.PrintWarning("cmd.keys_plaintext_warning")
+		} else {
+			fmt.Printf("🔐 %s:  %s\n", i18n.T("cmd.keys_file_label"), config.GetKeyPath(cfg.ActiveProfile))
+		}
+
+		// 轮换下来的历史密钥
+		if history, err := keystore.LoadHistory(keystore.HistoryPath(config.GetKeyPath(cfg.ActiveProfile))); err == nil && len(history.Keys) > 0 {
+			fmt.Printf("🗂️  %s:\n", i18n.T("cmd.keys_history_label"))
+			for _, h := range history.Keys {
+				status := "🔁 " + h.RetiredAt.Format(time.RFC3339)
+				if h.RevokedAt != nil {
+					status = "🚫 " + h.RevokedAt.Format(time.RFC3339)
+				}
+				fmt.Printf("   %s  %s\n", h.PublicKey, status)
+			}
+		}
 	},
 }
 
 // --- 辅助函数：修复了 cfg 的引用问题 ---
 
+// saveKeys 保存身份：私钥以口令加密写入 ~/.runly/keys/<profile>.key，配置中只保留 MeID 与公钥
 func saveKeys(cfg *config.CLIConfig, meID, pub, priv string) {
+	passphrase, err := promptNewKeyPassphrase()
+	if err != nil {
+		ui.PrintError("common.failure", err)
+		os.Exit(1)
+	}
+	saveKeysWith(cfg, meID, pub, priv, passphrase)
+}
+
+// saveKeysWith 使用已确定的口令保存身份 (轮换时需在登记前取得口令，避免服务器已轮换而本地保存失败)
+func saveKeysWith(cfg *config.CLIConfig, meID, pub, priv string, passphrase []byte) {
+	f, err := keystore.Seal(meID, pub, priv, passphrase)
+	if err == nil {
+		err = f.Save(config.GetKeyPath(cfg.ActiveProfile))
+	}
+	if err != nil {
+		ui.PrintError("errors.key_save_fail", err)
+		os.Exit(1)
+	}
+
 	p := cfg.Profiles[cfg.ActiveProfile]
 	p.MeID = meID
 	p.PublicKey = pub
-	p.SecretKey = priv
+	p.SecretKey = ""
 	cfg.Profiles[cfg.ActiveProfile] = p
-	_ = cfg.SaveConfig()
+	if err := cfg.SaveConfig(); err != nil {
+		ui.PrintError("errors.config_save_fail", err)
+		os.Exit(1)
+	}
+}
+
+// hasSigningKey 判断当前 Profile 是否有签名私钥 (加密密钥文件或待迁移的明文私钥)
+func hasSigningKey(cfg *config.CLIConfig) bool {
+	if cfg.GetActive().SecretKey != "" {
+		return true
+	}
+	_, err := os.Stat(config.GetKeyPath(cfg.ActiveProfile))
+	return err == nil
+}
+
+// signingKeyOrExit 解锁当前 Profile 的签名私钥，口令优先读取 RUNLY_KEY_PASSPHRASE；
+// 存量明文私钥在首次使用时迁移为加密密钥文件
+func signingKeyOrExit(cfg *config.CLIConfig) string {
+	if cfg.GetActive().SecretKey != "" {
+		migrateSecretKeyOrExit(cfg)
+	}
+
+	f, err := keystore.Load(config.GetKeyPath(cfg.ActiveProfile))
+	if err != nil {
+		ui.PrintError("errors.no_key")
+		os.Exit(1)
+	}
+	passphrase, err := keystore.PassphraseFunc()
+	if err != nil {
+		ui.PrintError("common.failure", err)
+		os.Exit(1)
+	}
+	seed, err := f.Open(passphrase)
+	if err != nil {
+		ui.PrintError("errors.key_unlock_fail", err)
+		os.Exit(1)
+	}
+	return seed
+}
+
+// migrateSecretKeyOrExit 将配置中的明文私钥加密写入密钥文件，并从配置中删除
+func migrateSecretKeyOrExit(cfg *config.CLIConfig) {
+	p := cfg.GetActive()
+	ui.PrintWarning("cmd.keys_plaintext_warning")
+	saveKeys(cfg, p.MeID, p.PublicKey, p.SecretKey)
+	ui.PrintSuccess("cmd.keys_migrated")
+	fmt.Printf("🔐 %s: %s\n", i18n.T("cmd.keys_file_label"), config.GetKeyPath(cfg.ActiveProfile))
+}
+
+// promptKeyPassphrase 环境变量未设置时交互式输入签名私钥口令
+func promptKeyPassphrase() ([]byte, error) {
+	if p := os.Getenv("RUNLY_KEY_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	}
+	if !isTerminal() {
+		return nil, keystore.ErrKeyLocked
+	}
+	p, err := pterm.DefaultInteractiveTextInput.WithMask("*").Show(i18n.T("cmd.keys_prompt_passphrase"))
+	if err != nil || p == "" {
+		return nil, keystore.ErrKeyLocked
+	}
+	return []byte(p), nil
+}
+
+// promptNewKeyPassphrase 设定加密签名私钥的口令，交互式输入时需确认一次
+func promptNewKeyPassphrase() ([]byte, error) {
+	if p := os.Getenv("RUNLY_KEY_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	}
+	if !isTerminal() {
+		return nil, keystore.ErrKeyLocked
+	}
+	p, err := pterm.DefaultInteractiveTextInput.WithMask("*").Show(i18n.T("cmd.keys_prompt_new_passphrase"))
+	if err != nil || p == "" {
+		return nil, keystore.ErrKeyLocked
+	}
+	confirm, err := pterm.DefaultInteractiveTextInput.WithMask("*").Show(i18n.T("cmd.keys_prompt_confirm"))
+	if err != nil || confirm != p {
+		return nil, fmt.Errorf(i18n.T("errors.key_passphrase_mismatch"))
+	}
+	return []byte(p), nil
+}
+
+// isTerminal 判断标准输入是否为终端，CI 等非交互环境中不弹出口令输入
+func isTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
 }
 
 func printCurrentIdentity(cfg *config.CLIConfig) {
 	profile := cfg.GetActive()
-	fmt.Printf("\n🆔 MeID:   %s\n", profile.MeID)
+	meID := profile.MeID
+	if meID == "" && profile.PublicKey != "" {
+		meID = i18n.T("cmd.keys_meid_pending")
+	}
+	fmt.Printf("\n🆔 MeID:   %s\n", meID)
 	fmt.Printf("🔑 %s: %s\n", i18n.T("manifest.pub_key_label"), profile.PublicKey)
 	fmt.Printf("🌐 %s: %s (%s)\n", i18n.T("common.status"), cfg.ActiveProfile, profile.MeServer)
 	fmt.Printf("\n💡 %s\n", i18n.T("cmd.keys_info_tip"))
 }
 
+// rotateCmd：轮换密钥：旧密钥签署轮换声明，新密钥对同一声明签名作为持有证明；
+// 旧公钥进入本地密钥历史，轮换前签名的存量资产仍可验证
+var rotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the signing key; the old key signs the rotation and stays in local history",
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.PrintHeader("cmd.keys_header")
+		cfg, _ := config.LoadConfig()
+		if !hasSigningKey(cfg) {
+			ui.PrintError("errors.no_key")
+			os.Exit(1)
+		}
+		// 待登记的密钥尚无 MeID，无法签署轮换声明
+		if cfg.GetActive().MeID == "" {
+			ui.PrintError("errors.key_unregistered")
+			os.Exit(1)
+		}
+
+		// 1. 解锁旧密钥，并先确定新密钥的口令
+		oldSeed := signingKeyOrExit(cfg)
+		profile := cfg.GetActive()
+		passphrase, err := promptNewKeyPassphrase()
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		// 2. 生成新密钥对并签署轮换声明
+		ui.PrintStep("cmd.keys_generating")
+		pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+		newPub := hex.EncodeToString(pub)
+		newSeed := hex.EncodeToString(priv.Seed())
+		now := time.Now().UTC().Truncate(time.Second)
+
+		statement, err := keystore.RotationStatement(profile.MeID, profile.PublicKey, newPub, now)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		signature, err := crypto.Sign(oldSeed, statement)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		proof, err := crypto.Sign(newSeed, statement)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		// 3. 向身份服务器登记轮换，失败时不改动本地身份
+		ui.PrintStep("common.syncing")
+		meID, err := adapter.NewClient().RotateKey(statement, signature, proof)
+		if err != nil {
+			ui.PrintError("errors.key_rotate_fail", err)
+			os.Exit(1)
+		}
+		if meID == "" {
+			meID = profile.MeID
+		}
+
+		// 4. 先加密保存新私钥，再将旧公钥写入本地历史；
+		// 新密钥保存失败时旧密钥仍是本机的当前密钥，不能提前标记为已轮换
+		history := loadKeyHistoryOrExit(cfg)
+		saveKeysWith(cfg, meID, newPub, newSeed, passphrase)
+		history.Keys = append(history.Keys, keystore.HistoryEntry{
+			MeID:      profile.MeID,
+			PublicKey: profile.PublicKey,
+			RetiredAt: now,
+			Statement: string(statement),
+			Signature: signature,
+		})
+		if err := history.Save(); err != nil {
+			ui.PrintError("errors.key_save_fail", err)
+			os.Exit(1)
+		}
+
+		ui.PrintSuccess("cmd.keys_rotated")
+		fmt.Printf("🔁 %s → %s\n", profile.PublicKey, newPub)
+		printCurrentIdentity(cfg)
+	},
+}
+
+// revokeCmd：吊销历史密钥，--at 可回溯到密钥泄露时间；以该密钥签名的资产在 check 中被拒绝，除非可信时间早于吊销
+var revokeCmd = &cobra.Command{
+	Use:   "revoke [public_key]",
+	Short: "Revoke a previous key; its signatures are rejected unless a trusted timestamp predates the revocation",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.PrintHeader("cmd.keys_header")
+		cfg, _ := config.LoadConfig()
+		profile := cfg.GetActive()
+		publicKey := strings.ToLower(args[0])
+
+		// 1. 当前密钥需先轮换再吊销，否则本机将没有可用的签名密钥
+		if publicKey == profile.PublicKey {
+			ui.PrintError("errors.key_revoke_current")
+			os.Exit(1)
+		}
+		history := loadKeyHistoryOrExit(cfg)
+		entry := history.Find(publicKey)
+		if entry == nil {
+			ui.PrintError("errors.key_not_in_history", publicKey)
+			os.Exit(1)
+		}
+
+		at := time.Now().UTC().Truncate(time.Second)
+		if keysRevokeAt != "" {
+			t, err := time.Parse(time.RFC3339, keysRevokeAt)
+			if err != nil {
+				ui.PrintError("common.failure", err)
+				os.Exit(1)
+			}
+			at = t.UTC()
+		}
+
+		// 2. 当前密钥签署吊销声明并登记到身份服务器；登记失败时仍在本地生效
+		statement, err := keystore.RevocationStatement(entry.MeID, publicKey, at)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		signature, err := crypto.Sign(signingKeyOrExit(cfg), statement)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		ui.PrintStep("common.syncing")
+		if err := adapter.NewClient().RevokeKey(statement, signature); err != nil {
+			ui.PrintWarning("cmd.keys_revoke_unpublished", err)
+		}
+
+		// 3. 写入本地历史
+		entry.RevokedAt = &at
+		if err := history.Save(); err != nil {
+			ui.PrintError("errors.key_save_fail", err)
+			os.Exit(1)
+		}
+		ui.PrintSuccess("cmd.keys_revoked")
+		fmt.Printf("🚫 %s  %s\n", publicKey, at.Format(time.RFC3339))
+	},
+}
+
+// loadKeyHistoryOrExit 读取当前 Profile 的密钥历史
+func loadKeyHistoryOrExit(cfg *config.CLIConfig) *keystore.History {
+	history, err := keystore.LoadHistory(keystore.HistoryPath(config.GetKeyPath(cfg.ActiveProfile)))
+	if err != nil {
+		ui.PrintError("errors.load_fail", err)
+		os.Exit(1)
+	}
+	return history
+}
+
+// exportCmd：导出加密密钥文件 (保持口令加密，用于备份或迁移到其他设备)
+var exportCmd = &cobra.Command{
+	Use:   "export [file]",
+	Short: "Export the passphrase-encrypted signing key (stdout when file is omitted)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _ := config.LoadConfig()
+		if cfg.GetActive().SecretKey != "" {
+			migrateSecretKeyOrExit(cfg)
+		}
+
+		data, err := os.ReadFile(config.GetKeyPath(cfg.ActiveProfile))
+		if err != nil {
+			ui.PrintError("errors.no_key")
+			os.Exit(1)
+		}
+		if len(args) == 0 {
+			fmt.Println(string(data))
+			return
+		}
+		if err := os.WriteFile(args[0], data, 0600); err != nil {
+			ui.PrintError("errors.key_save_fail", err)
+			os.Exit(1)
+		}
+		ui.PrintSuccess("cmd.keys_export_success")
+		fmt.Printf("📄 %s: %s\n", i18n.T("common.output"), args[0])
+	},
+}
+
+// importCmd：导入加密密钥文件，解锁验证后设为当前 Profile 的身份
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import a passphrase-encrypted signing key into the active profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.PrintHeader("cmd.keys_header")
+		cfg, _ := config.LoadConfig()
+		profile := cfg.GetActive()
+
+		f, err := keystore.Load(args[0])
+		if err != nil {
+			ui.PrintError("errors.load_fail", err)
+			os.Exit(1)
+		}
+		if profile.PublicKey != "" && profile.PublicKey != f.PublicKey && !keysForce {
+			ui.PrintError("errors.key_import_mismatch", f.PublicKey, profile.PublicKey)
+			os.Exit(1)
+		}
+
+		// 解锁一次以确认口令正确、私钥与公钥匹配 (Load 已校验加密参数，不可信文件无法触发超大开销的密钥派生)
+		passphrase, err := keystore.PassphraseFunc()
+		if err == nil {
+			_, err = f.Open(passphrase)
+		}
+		if err != nil {
+			ui.PrintError("errors.key_unlock_fail", err)
+			os.Exit(1)
+		}
+		if err := f.Save(config.GetKeyPath(cfg.ActiveProfile)); err != nil {
+			ui.PrintError("errors.key_save_fail", err)
+			os.Exit(1)
+		}
+
+		profile.MeID = f.MeID
+		profile.PublicKey = f.PublicKey
+		profile.SecretKey = ""
+		cfg.Profiles[cfg.ActiveProfile] = profile
+		if err := cfg.SaveConfig(); err != nil {
+			ui.PrintError("errors.config_save_fail", err)
+			os.Exit(1)
+		}
+
+		ui.PrintSuccess("cmd.keys_import_success")
+		printCurrentIdentity(cfg)
+	},
+}
+
+// migrateKeysCmd：将配置中的明文私钥迁移为加密密钥文件
+var migrateKeysCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Encrypt a plaintext secret key stored in config.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _ := config.LoadConfig()
+		if cfg.GetActive().SecretKey == "" {
+			ui.PrintSuccess("common.success")
+			return
+		}
+		migrateSecretKeyOrExit(cfg)
+	},
+}
+
 func init() {
+	keystore.PassphraseFunc = promptKeyPassphrase
+
+	revokeCmd.Flags().StringVar(&keysRevokeAt, "at", "", "Revocation time (RFC 3339), e.g. when the key was compromised (default: now)")
+	importCmd.Flags().BoolVar(&keysForce, "force", false, "Replace the current identity with the imported key")
+
+	generateCmd.Flags().BoolVar(&keysLocal, "local", true, "Keep the private key on this machine and register only the public key (--local=false uploads it for cloud sync)")
+
 	keysCmd.AddCommand(generateCmd)
+	keysCmd.AddCommand(registerCmd)
+	keysCmd.AddCommand(rotateCmd)
+	keysCmd.AddCommand(revokeCmd)
 	keysCmd.AddCommand(showCmd)
+	keysCmd.AddCommand(exportCmd)
+	keysCmd.AddCommand(importCmd)
+	keysCmd.AddCommand(migrateKeysCmd)
 	rootCmd.AddCommand(keysCmd)
 }
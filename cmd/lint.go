@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/lint"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/spf13/cobra"
+)
+
+var lintFormat string
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file.runly]",
+	Short: "🧽 Check SOP assets against best-practice and safety rules",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := args[0]
+		textOutput := lintFormat != "json" && lintFormat != "sarif"
+
+		// 1. 打印多语言 Header，JSON/SARIF 输出时保持 stdout 纯净
+		if textOutput {
+			ui.PrintHeader("cmd.lint_header")
+		}
+
+		// 2. 加载协议资产
+		proto, err := protocol.Load(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ [%s]:\n%s\n", i18n.T("common.failure"), protocol.FormatError(err))
+			os.Exit(1)
+		}
+
+		// 3. 协议校验 + 最佳实践检查 (规则级别可由 .runlyrc 与内联注释调整)
+		diags, err := lint.Run(proto)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		// 4. 按格式输出
+		switch lintFormat {
+		case "json":
+			if diags == nil {
+				diags = protocol.Diagnostics{}
+			}
+			data, _ := json.MarshalIndent(diags, "", "  ")
+			fmt.Println(string(data))
+		case "sarif":
+			data, _ := json.MarshalIndent(lint.ToSARIF(diags, Version), "", "  ")
+			fmt.Println(string(data))
+		default:
+			if len(diags) == 0 {
+				ui.PrintSuccess("cmd.lint_clean")
+				return
+			}
+			fmt.Printf("\n%s\n\n", diags.Render())
+			fmt.Printf(i18n.T("cmd.check_summary")+"\n", diags.Count(protocol.SeverityError), diags.Count(protocol.SeverityWarning), diags.Count(protocol.SeverityInfo))
+		}
+
+		// 5. 存在 error 级诊断时以非零状态退出 (用于 CI)
+		if diags.HasErrors() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "text", "Output format: text | json | sarif")
+	rootCmd.AddCommand(lintCmd)
+}
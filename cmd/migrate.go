@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/migrate"
+	"github.com/originbeat-inc/runly-cli/pkg/textdiff"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	migrateTo     string
+	migrateDryRun bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [file.runly]",
+	Short: "🧬 Upgrade a protocol file to a newer spec version",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := args[0]
+		ui.PrintHeader("cmd.migrate_header")
+
+		// 1. 读取原始文件 (迁移基于 YAML 语法树进行，保留注释与字段顺序)
+		data, err := os.ReadFile(file)
+		if err != nil {
+			ui.PrintError("errors.load_fail", err)
+			os.Exit(1)
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			ui.PrintError("errors.load_fail", err)
+			os.Exit(1)
+		}
+
+		// 2. 计算迁移路径并依次执行
+		res, err := migrate.Run(data, migrateTo)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		if len(res.Applied) == 0 {
+			ui.PrintStep("cmd.migrate_up_to_date", res.From)
+			return
+		}
+
+		fmt.Printf(i18n.T("cmd.migrate_applied")+"\n", res.From, res.To)
+		for _, m := range res.Applied {
+			fmt.Printf("  • %-20s %s\n", m.ID, m.Description)
+		}
+		fmt.Println()
+
+		// 3. --dry-run 仅输出差异，不落盘
+		if migrateDryRun {
+			fmt.Print(textdiff.Unified(file, file+" (migrated)", string(data), string(res.Output), 3))
+			return
+		}
+
+		if err := os.WriteFile(file, res.Output, info.Mode().Perm()); err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		// 4. 迁移改变了文件内容，已签名的资产需要重新构建签名
+		if signed(data) {
+			ui.PrintWarning("cmd.migrate_resign")
+		}
+		ui.PrintSuccess("common.success")
+	},
+}
+
+// signed 判断原始资产是否带有签名
+func signed(data []byte) bool {
+	var doc struct {
+		Security struct {
+			Signature string `yaml:"signature"`
+		} `yaml:"security"`
+	}
+	return yaml.Unmarshal(data, &doc) == nil && doc.Security.Signature != ""
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateTo, "to", "latest", "Target spec version")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show the resulting diff without writing the file")
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/originbeat-inc/runly-cli/internal/config"
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
@@ -30,7 +32,7 @@ var publishCmd = &cobra.Command{
 		// 3. 加载协议资产
 		proto, err := protocol.Load(file)
 		if err != nil {
-			ui.PrintError("errors.load_fail", err)
+			ui.PrintError("errors.load_fail", protocol.FormatError(err))
 			os.Exit(1)
 		}
 
@@ -50,6 +52,17 @@ var publishCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		// 密钥泄露拦截：明文凭证与已解析的密钥值不得随资产上传 (旧版固化资产同样检查)
+		if hardcoded := protocol.HardcodedCredentials(proto); len(hardcoded) > 0 {
+			ui.PrintError("errors.secret_hardcoded", strings.Join(hardcoded, ", "))
+			os.Exit(1)
+		}
+		serialized, _ := json.Marshal(proto)
+		if leaked := protocol.DetectSecretLeaks(serialized, proto); len(leaked) > 0 {
+			ui.PrintError("errors.secret_leak", strings.Join(leaked, ", "))
+			os.Exit(1)
+		}
+
 		// 5. 执行多语言进度上传
 		client := adapter.NewClient()
 		client.BaseURL = profile.HubServer // 切换至当前 Profile 指定的 Hub 节点地址
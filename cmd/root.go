@@ -9,6 +9,7 @@ import (
 	"github.com/originbeat-inc/runly-cli/internal/config"
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
 	"github.com/spf13/cobra"
 )
 
@@ -43,6 +44,7 @@ func Execute() {
 
 	// 4. 配置子命令和描述翻译
 	rootCmd.Version = Version
+	protocol.RuntimeVersion = Version
 	refreshI18nDescriptions()
 
 	// 5. 初始化内置命令
@@ -58,10 +60,17 @@ func Execute() {
 }
 
 // isVersionRequest 检查是否请求了版本信息
+// 仅拦截出现在子命令之前的标志，子命令自身的 --version (如 schema --version) 不受影响
 func isVersionRequest() bool {
-	for _, arg := range os.Args {
-		if arg == "-v" || arg == "--version" {
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-v" || arg == "--version":
 			return true
+		case arg == "-l" || arg == "--lang":
+			i++ // 跳过语言参数值
+		case !strings.HasPrefix(arg, "-"):
+			return false
 		}
 	}
 	return false
@@ -160,4 +169,5 @@ func ensureConfigInitialized() {
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&userLang, "lang", "l", "", "Force language")
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Enable verbose output")
+	rootCmd.PersistentFlags().BoolVar(&protocol.DefaultLoadOptions.Lenient, "lenient", false, "Treat unknown protocol fields as warnings (forward-compatible assets)")
 }
@@ -1,34 +1,69 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/olekukonko/tablewriter"
+	"github.com/originbeat-inc/runly-cli/internal/config"
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/cache"
 	"github.com/originbeat-inc/runly-cli/pkg/executor"
 	"github.com/originbeat-inc/runly-cli/pkg/protocol"
 	"github.com/spf13/cobra"
 )
 
+var (
+	runTraceFile string
+	runDryRun    bool
+	runFormat    string
+	runInputs    []string
+	runBudget    float64
+	runTokenCost float64
+	runLive      bool
+	runCache     bool
+	runCacheTTL  int
+)
+
 var runCmd = &cobra.Command{
 	Use:   "run [file.runly]",
 	Short: "🚀 Execute SOP in sandbox with full AI engine support",
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		file := args[0]
+		jsonOutput := runDryRun && runFormat == "json"
 
-		// 1. 打印多语言 Header (🚀 RUNLY 本地仿真运行)
-		ui.PrintHeader("cmd.run_header")
+		// 1. 打印多语言 Header (🚀 RUNLY 本地仿真运行)，JSON 输出时保持 stdout 纯净
+		if !jsonOutput {
+			ui.PrintHeader("cmd.run_header")
+		}
 
-		// 2. 加载协议资产 (自动处理环境变量注入)
+		// 2. 加载协议资产 (密钥占位符保持原样，发送请求时才解析)
 		proto, err := protocol.Load(file)
 		if err != nil {
-			ui.PrintError("errors.load_fail", err)
+			ui.PrintError("errors.load_fail", protocol.FormatError(err))
+			os.Exit(1)
+		}
+
+		// 运行时版本门禁：资产要求更高版本的 CLI 时拒绝执行
+		if err := protocol.CheckRuntime(proto); err != nil {
+			ui.PrintError("common.failure", protocol.FormatError(err))
 			os.Exit(1)
 		}
 
-		// 3. 准备运行上下文：注入 Dictionary 定义的默认输入
+		// 宽松模式下被放行的未知字段等加载诊断仅作提示
+		if !jsonOutput {
+			for _, d := range proto.Findings {
+				ui.PrintWarning("common.warning", fmt.Sprintf("%s:%d: [%s] %s", d.Pos.File, d.Pos.Line, d.Code, d.Message))
+			}
+		}
+
+		// 3. 准备运行上下文：注入 Dictionary 定义的默认输入，再叠加 --input 传入的值
 		// 这确保了即使不传递外部参数，SOP 也能依靠默认配置运行
 		inputs := make(map[string]interface{})
 		for _, in := range proto.Dictionary.Inputs {
@@ -36,16 +71,71 @@ var runCmd = &cobra.Command{
 				inputs[in.Name] = in.Default
 			}
 		}
+		for _, kv := range runInputs {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok {
+				ui.PrintError("errors.input_flag_format", kv)
+				os.Exit(1)
+			}
+			inputs[key] = value
+		}
+
+		// 密钥预检：执行前一次性列出全部无法解析的密钥，dry-run 仅提示
+		if runLive {
+			if err := protocol.ValidateSecrets(proto); err != nil {
+				if !runDryRun {
+					ui.PrintError("common.failure", err)
+					os.Exit(1)
+				}
+				if !jsonOutput {
+					ui.PrintWarning("common.warning", err)
+				}
+			}
+		}
+
+		// Dry-run：只生成执行计划，不发送任何请求
+		if runDryRun {
+			plan := executor.BuildPlan(proto, inputs)
+			if jsonOutput {
+				data, _ := json.MarshalIndent(plan, "", "  ")
+				fmt.Println(string(data))
+			} else {
+				printPlan(plan)
+			}
+			return
+		}
 
 		// 4. 初始化多语言执行引擎
 		engine := executor.NewEngine(proto, inputs)
+		engine.Meter = executor.NewMeter(proto, runTokenCost, runBudget)
+		engine.Live = runLive
+		if runCache {
+			// 仿真模式不发送请求，缓存不会生效
+			if !runLive {
+				ui.PrintWarning("cmd.run_cache_needs_live")
+			}
+			engine.Cache = cache.NewStore(config.GetCacheDir())
+			engine.CacheTTL = runCacheTTL
+		}
 
 		// 提示：⚙️ RUNLY 执行引擎
 		ui.PrintStep("executor.engine_header")
 
-		if err := engine.Run(); err != nil {
+		err = engine.Run()
+
+		// 运行轨迹无论成功与否都落盘，失败路径同样计入覆盖率
+		if runTraceFile != "" {
+			if saveErr := engine.Trace.Save(runTraceFile); saveErr != nil {
+				ui.PrintWarning("common.warning", saveErr)
+			} else {
+				ui.PrintStep("cmd.run_trace_saved", runTraceFile)
+			}
+		}
+
+		if err != nil {
 			// 提示：❌ 失败
 			ui.PrintError("common.failure", err)
+			printCostSummary(engine.Meter.Summary)
 			os.Exit(1)
 		}
 
@@ -65,11 +155,118 @@ var runCmd = &cobra.Command{
 			}
 		}
 
-		// 6. 成功结语：✨ SOP 执行链路已完整结束
+		// 6. 成本汇总：Token 用量、技能调用费用与总耗时
+		printCostSummary(engine.Meter.Summary)
+
+		// 7. 成功结语：✨ SOP 执行链路已完整结束
 		fmt.Printf("\n✨ %s\n", i18n.T("executor.execution_complete"))
 	},
 }
 
+// printCostSummary 输出本次运行的成本明细
+func printCostSummary(sum *executor.CostSummary) {
+	ui.PrintHeader("executor.cost_header")
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{i18n.T("executor.plan_node"), i18n.T("executor.plan_type"), i18n.T("executor.cost_tokens"), i18n.T("executor.cost_amount"), i18n.T("executor.cost_duration")})
+	table.SetBorder(false)
+	for _, n := range sum.Nodes {
+		table.Append([]string{
+			n.NodeID,
+			n.Type,
+			fmt.Sprintf("%d", n.PromptTokens+n.CompletionTokens),
+			fmt.Sprintf("%.4f", n.Cost),
+			n.Duration.Round(time.Millisecond).String(),
+		})
+	}
+	table.Render()
+
+	fmt.Println()
+	ui.PrintKV("executor.cost_tokens", fmt.Sprintf("%d (prompt %d / completion %d)", sum.PromptTokens+sum.CompletionTokens, sum.PromptTokens, sum.CompletionTokens))
+	ui.PrintKV("executor.cost_total", fmt.Sprintf("%.4f %s", sum.Total, sum.Currency))
+	if sum.Budget > 0 {
+		ui.PrintKV("executor.cost_budget", fmt.Sprintf("%.4f %s", sum.Budget, sum.Currency))
+	}
+	ui.PrintKV("executor.cost_wall_time", sum.WallTime.Round(time.Millisecond).String())
+}
+
+// printPlan 以表格形式展示 dry-run 执行计划及其将要发送的请求
+func printPlan(plan *executor.Plan) {
+	ui.PrintHeader("executor.plan_header")
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"#", i18n.T("executor.plan_node"), i18n.T("executor.plan_type"), i18n.T("executor.plan_action"), i18n.T("executor.plan_next")})
+	table.SetAutoWrapText(false)
+	table.SetRowLine(true)
+
+	for i, step := range plan.Steps {
+		var action []string
+		if step.Prompt != "" {
+			action = append(action, step.Prompt)
+		}
+		for _, r := range step.Requests {
+			action = append(action, fmt.Sprintf("%s %s", r.Method, r.Endpoint))
+		}
+		if step.Artifact != "" {
+			action = append(action, "🎁 "+step.Artifact)
+		}
+
+		var next []string
+		for _, b := range step.Branches {
+			if b.Condition != "" {
+				next = append(next, fmt.Sprintf("[%s] → %s", b.Condition, b.Next))
+			} else {
+				next = append(next, fmt.Sprintf("%s → %s", b.Edge, b.Next))
+			}
+		}
+
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			step.NodeID,
+			step.Type,
+			strings.Join(action, "\n"),
+			strings.Join(next, "\n"),
+		})
+	}
+	table.Render()
+
+	// 逐个展示请求详情 (请求头已脱敏)
+	for _, step := range plan.Steps {
+		for _, r := range step.Requests {
+			fmt.Printf("\n📡 [%s] %s %s\n", step.NodeID, r.Method, r.Endpoint)
+			keys := make([]string, 0, len(r.Headers))
+			for k := range r.Headers {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fmt.Printf("   %s: %s\n", k, r.Headers[k])
+			}
+			if r.Body != nil {
+				fmt.Print("   ")
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetEscapeHTML(false)
+				enc.SetIndent("   ", "  ")
+				_ = enc.Encode(r.Body)
+			}
+		}
+	}
+
+	if len(plan.Unreachable) > 0 {
+		fmt.Println()
+		ui.PrintWarning("executor.plan_unreachable", strings.Join(plan.Unreachable, ", "))
+	}
+}
+
 func init() {
+	runCmd.Flags().BoolVar(&runDryRun, "dry-run", false, "Walk the topology and show the planned requests without executing anything")
+	runCmd.Flags().StringVarP(&runFormat, "format", "f", "table", "Dry-run output format: table | json")
+	runCmd.Flags().StringArrayVarP(&runInputs, "input", "i", nil, "Override an input value (key=value, repeatable)")
+	runCmd.Flags().Float64Var(&runBudget, "budget", 0, "Abort the run when projected spend exceeds this amount")
+	runCmd.Flags().Float64Var(&runTokenCost, "token-price", 0, "Default LLM price per 1K tokens (overridden by node config.token_price)")
+	runCmd.Flags().BoolVar(&runLive, "live", false, "Send real skill and knowledge requests (AI tasks stay simulated); default is a sandboxed simulation")
+	runCmd.Flags().BoolVar(&runCache, "cache", false, "Reuse cached skill and knowledge responses with --live (see 'runly-cli cache')")
+	runCmd.Flags().IntVar(&runCacheTTL, "cache-ttl", 0, "Default cache TTL in seconds for resources without cache_ttl")
+	runCmd.Flags().StringVar(&runTraceFile, "trace", "", "Record the execution trace to a JSON file")
 	rootCmd.AddCommand(runCmd)
 }
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/originbeat-inc/runly-cli/internal/config"
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/secrets"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+var secretValue string
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "🔐 Manage secrets stored in the encrypted local vault",
+}
+
+// secretsSetCmd: 写入保险库
+var secretsSetCmd = &cobra.Command{
+	Use:   "set [name]",
+	Short: "Store a secret in the vault (referenced as {{secret.vault.NAME}})",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// 名称必须符合占位符语法，否则写入后无法被引用
+		if !secrets.ValidName(args[0]) {
+			ui.PrintError("errors.secret_name_invalid", args[0])
+			os.Exit(1)
+		}
+		vault, passphrase := openVaultOrExit(true)
+
+		value := secretValue
+		if value == "" {
+			value, _ = pterm.DefaultInteractiveTextInput.WithMask("*").Show(i18n.T("cmd.secrets_prompt_value"))
+		}
+		vault.Secrets[args[0]] = value
+
+		if err := vault.Save(passphrase); err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		ui.PrintSuccess("common.success")
+		fmt.Printf("🔐 {{secret.vault.%s}}\n", args[0])
+	},
+}
+
+// secretsRmCmd: 从保险库删除
+var secretsRmCmd = &cobra.Command{
+	Use:   "rm [name]",
+	Short: "Remove a secret from the vault",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		vault, passphrase := openVaultOrExit(false)
+
+		if _, ok := vault.Secrets[args[0]]; !ok {
+			ui.PrintError("errors.secret_unresolved", "vault", args[0], secrets.ErrNotFound)
+			os.Exit(1)
+		}
+		delete(vault.Secrets, args[0])
+
+		if err := vault.Save(passphrase); err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		ui.PrintSuccess("common.success")
+	},
+}
+
+// secretsLsCmd: 列出保险库中的密钥名称 (不显示值)
+var secretsLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List secret names in the vault and the available providers",
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.PrintHeader("cmd.secrets_header")
+		vault, _ := openVaultOrExit(false)
+
+		ui.PrintKV("cmd.secrets_providers", secrets.Default.Names())
+		fmt.Println()
+		for _, name := range vault.Names() {
+			fmt.Printf("   🔑 {{secret.vault.%s}}\n", name)
+		}
+	},
+}
+
+// openVaultOrExit 解锁保险库，口令优先读取 RUNLY_VAULT_PASSPHRASE
+// create 为 true 且保险库尚不存在时要求重复输入口令，避免输错后以未知口令加密
+func openVaultOrExit(create bool) (*secrets.Vault, []byte) {
+	getPassphrase := secrets.PassphraseFunc
+	if _, err := os.Stat(config.GetVaultPath()); create && os.IsNotExist(err) {
+		getPassphrase = promptNewVaultPassphrase
+	}
+	passphrase, err := getPassphrase()
+	if err != nil {
+		ui.PrintError("common.failure", err)
+		os.Exit(1)
+	}
+
+	vault, err := secrets.OpenVault(config.GetVaultPath(), passphrase)
+	if err != nil {
+		ui.PrintError("errors.vault_open_fail", err)
+		os.Exit(1)
+	}
+	return vault, passphrase
+}
+
+// promptVaultPassphrase 环境变量未设置时交互式输入保险库口令
+func promptVaultPassphrase() ([]byte, error) {
+	if p := os.Getenv("RUNLY_VAULT_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	}
+	p, err := pterm.DefaultInteractiveTextInput.WithMask("*").Show(i18n.T("cmd.secrets_prompt_passphrase"))
+	if err != nil || p == "" {
+		return nil, secrets.ErrVaultLocked
+	}
+	return []byte(p), nil
+}
+
+// promptNewVaultPassphrase 新建保险库时的口令：环境变量未设置时交互输入两次并比对
+func promptNewVaultPassphrase() ([]byte, error) {
+	if p := os.Getenv("RUNLY_VAULT_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	}
+	p, err := pterm.DefaultInteractiveTextInput.WithMask("*").Show(i18n.T("cmd.secrets_prompt_new_passphrase"))
+	if err != nil || p == "" {
+		return nil, secrets.ErrVaultLocked
+	}
+	confirm, err := pterm.DefaultInteractiveTextInput.WithMask("*").Show(i18n.T("cmd.keys_prompt_confirm"))
+	if err != nil || confirm != p {
+		return nil, fmt.Errorf(i18n.T("errors.key_passphrase_mismatch"))
+	}
+	return []byte(p), nil
+}
+
+func init() {
+	secrets.PassphraseFunc = promptVaultPassphrase
+
+	secretsSetCmd.Flags().StringVar(&secretValue, "value", "", "Secret value (prompted when omitted)")
+
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsRmCmd)
+	secretsCmd.AddCommand(secretsLsCmd)
+	rootCmd.AddCommand(secretsCmd)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/config"
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/compiler"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	signRole     string
+	signDetached bool
+)
+
+var signCmd = &cobra.Command{
+	Use:   "sign [dist.runly]",
+	Short: "✍️  Co-sign a built asset as reviewer or organization",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := args[0]
+
+		// 1. 打印多语言 Header (✍️ RUNLY 资产联署)
+		ui.PrintHeader("cmd.sign_header")
+
+		if !protocol.ValidRole(signRole) {
+			ui.PrintError("errors.signature_policy_invalid", signRole)
+			os.Exit(1)
+		}
+
+		// 2. 获取当前 Profile 身份用于签名
+		cfg, _ := config.LoadConfig()
+		profile := cfg.GetActive()
+		if !hasSigningKey(cfg) {
+			ui.PrintError("errors.no_key")
+			os.Exit(1)
+		}
+		if profile.MeID == "" {
+			ui.PrintError("errors.key_unregistered")
+			os.Exit(1)
+		}
+
+		// 3. 加载资产：只为内容完整的已构建资产联署
+		proto, err := protocol.Load(file)
+		if err != nil {
+			ui.PrintError("errors.load_fail", protocol.FormatError(err))
+			os.Exit(1)
+		}
+		ui.PrintStep("cmd.signing_step")
+		if ok, err := compiler.VerifyIntegrity(proto); !ok {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		// 联署必须来自创建者以外的身份，creator 签名只能由创建者本人补签
+		creatorID := proto.Manifest.Creator.MeID
+		if signRole == protocol.RoleCreator && profile.MeID != creatorID {
+			ui.PrintError("common.failure", fmt.Sprintf(i18n.T("errors.cosign_creator_mismatch"), profile.MeID, creatorID))
+			os.Exit(1)
+		}
+		if signRole != protocol.RoleCreator && profile.MeID == creatorID {
+			ui.PrintError("common.failure", fmt.Sprintf(i18n.T("errors.cosign_self_review"), profile.MeID, signRole))
+			os.Exit(1)
+		}
+
+		// 4. 对内容摘要签名
+		digest, err := compiler.Digest(proto)
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		sig, err := compiler.NewSignature(digest, signRole, profile.MeID, profile.PublicKey, signingKeyOrExit(cfg), time.Now())
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		// 5. 写回资产，或写入分离签名文件 (资产字节保持不变)
+		output := file
+		if signDetached {
+			output = compiler.DetachedPath(file)
+			err = writeDetached(output, proto, digest, sig)
+		} else {
+			proto.Security.Signatures = compiler.AttachSignature(proto.Security.Signatures, sig)
+			data, _ := yaml.Marshal(proto)
+			err = os.WriteFile(file, data, 0644)
+		}
+		if err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		// 6. 成功反馈
+		ui.PrintSuccess("cmd.sign_success")
+		fmt.Printf("✍️  %s: %s (%s)\n", i18n.T("cmd.sign_signer"), sig.SignerID, sig.Role)
+		fmt.Printf("🔑 %s: %s\n", i18n.T("manifest.pub_key_label"), sig.KeyID)
+		fmt.Printf("📄 %s: %s\n", i18n.T("common.output"), output)
+	},
+}
+
+// writeDetached 将签名追加到分离签名文件，已有文件对应其他内容时报错而不是覆盖他人签名
+func writeDetached(path string, proto *protocol.RunlyProtocol, digest string, sig protocol.Signature) error {
+	d, err := compiler.LoadDetached(path)
+	switch {
+	case os.IsNotExist(err):
+		if d, err = compiler.NewDetached(proto); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		if err := d.Matches(digest); err != nil {
+			return err
+		}
+	}
+	d.Signatures = compiler.AttachSignature(d.Signatures, sig)
+	return d.Save(path)
+}
+
+func init() {
+	signCmd.Flags().StringVar(&signRole, "role", protocol.RoleReviewer, "Signature role: creator, reviewer or organization")
+	signCmd.Flags().BoolVar(&signDetached, "detached", false, "Write the signature to <file>.sig instead of embedding it in the asset")
+	rootCmd.AddCommand(signCmd)
+}
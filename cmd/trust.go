@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/originbeat-inc/runly-cli/internal/config"
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/executor/adapter"
+	"github.com/originbeat-inc/runly-cli/pkg/keystore"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/originbeat-inc/runly-cli/pkg/trust"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trustKey   string
+	trustName  string
+	trustRoles []string
+)
+
+var trustCmd = &cobra.Command{
+	Use:   "trust",
+	Short: "🛡️  Manage trusted signer keys used to verify assets",
+}
+
+// trustAddCmd: 固定签名者公钥，未指定 --key 时从身份服务器解析
+var trustAddCmd = &cobra.Command{
+	Use:   "add [me_id]",
+	Short: "Pin a signer's public key (resolved from the Me server when --key is omitted)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		meID := args[0]
+		store := loadTrustStoreOrExit()
+
+		// 联署角色授权：只有 reviewer 与 organization 需要授权，creator 身份由签名者本人决定
+		for _, role := range trustRoles {
+			if role != protocol.RoleReviewer && role != protocol.RoleOrganization {
+				ui.PrintError("errors.signature_policy_invalid", role)
+				os.Exit(1)
+			}
+		}
+
+		keys := []trust.Key{{MeID: meID, PublicKey: trustKey, Name: trustName, Roles: trustRoles, Source: trust.SourcePinned}}
+		if trustKey == "" {
+			ui.PrintStep("common.syncing")
+			resolved, err := resolveKeys(meID)
+			if err != nil {
+				ui.PrintError("errors.trust_resolve_fail", meID, err)
+				os.Exit(1)
+			}
+			keys = resolved
+			for i := range keys {
+				keys[i].Name = trustName
+				keys[i].Roles = trustRoles
+			}
+		}
+
+		for _, k := range keys {
+			if err := store.Add(k); err != nil {
+				ui.PrintError("common.failure", err)
+				os.Exit(1)
+			}
+		}
+		if err := store.Save(); err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+
+		ui.PrintSuccess("common.success")
+		for _, k := range keys {
+			mark := "📌"
+			if k.RevokedAt != nil {
+				mark = "🚫"
+			} else if k.RetiredAt != nil {
+				mark = "🔁"
+			}
+			fmt.Printf("%s %s  %s  %s\n", mark, meID, strings.ToLower(k.PublicKey), strings.Join(k.Roles, ","))
+		}
+	},
+}
+
+// trustRmCmd: 移除签名者的固定公钥
+var trustRmCmd = &cobra.Command{
+	Use:   "rm [me_id]",
+	Short: "Remove pinned keys of a signer (only the given --key when set)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store := loadTrustStoreOrExit()
+
+		if store.Remove(args[0], trustKey) == 0 {
+			ui.PrintError("errors.trust_not_found", args[0])
+			os.Exit(1)
+		}
+		if err := store.Save(); err != nil {
+			ui.PrintError("common.failure", err)
+			os.Exit(1)
+		}
+		ui.PrintSuccess("common.success")
+	},
+}
+
+// trustLsCmd: 列出信任库
+var trustLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List trusted signers and their pinned keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		ui.PrintHeader("cmd.trust_header")
+		store := loadTrustStoreOrExit()
+
+		if len(store.Keys) == 0 {
+			ui.PrintStep("cmd.trust_empty")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"MeID", i18n.T("manifest.pub_key_label"), i18n.T("cmd.trust_source"), i18n.T("cmd.trust_roles"), i18n.T("cmd.trust_name"), i18n.T("cmd.trust_added")})
+		table.SetBorder(false)
+		for _, k := range store.Keys {
+			source := k.Source
+			if k.RevokedAt != nil {
+				source += " 🚫 " + k.RevokedAt.Format("2006-01-02")
+			} else if k.RetiredAt != nil {
+				source += " 🔁 " + k.RetiredAt.Format("2006-01-02")
+			}
+			table.Append([]string{k.MeID, k.PublicKey, source, strings.Join(k.Roles, ","), k.Name, k.AddedAt.Format("2006-01-02")})
+		}
+		table.Render()
+	},
+}
+
+// loadTrustStoreOrExit 读取本地信任库
+func loadTrustStoreOrExit() *trust.Store {
+	store, err := trust.Load(config.GetTrustPath())
+	if err != nil {
+		ui.PrintError("errors.trust_store_fail", err)
+		os.Exit(1)
+	}
+	return store
+}
+
+// newVerifier 构造签名者信任判定：信任库 + 当前 Profile 身份及其历史密钥，offline 为 false 时可通过身份服务器解析未固定的签名者
+func newVerifier(offline bool) *trust.Verifier {
+	v := &trust.Verifier{Store: loadTrustStoreOrExit()}
+
+	cfg, _ := config.LoadConfig()
+	if p := cfg.GetActive(); p.MeID != "" && p.PublicKey != "" {
+		v.Local = append(v.Local, trust.Key{MeID: p.MeID, PublicKey: p.PublicKey, Source: trust.SourceLocal})
+	}
+	if history, err := keystore.LoadHistory(keystore.HistoryPath(config.GetKeyPath(cfg.ActiveProfile))); err == nil {
+		for _, h := range history.Keys {
+			retired := h.RetiredAt
+			v.Local = append(v.Local, trust.Key{MeID: h.MeID, PublicKey: h.PublicKey, Source: trust.SourceLocal, RetiredAt: &retired, RevokedAt: h.RevokedAt})
+		}
+	}
+	if !offline {
+		v.Resolve = resolveKeys
+	}
+	return v
+}
+
+// resolveKeys 通过身份服务器解析 MeID 登记过的公钥 (含轮换下来与已吊销的公钥)
+func resolveKeys(meID string) ([]trust.Key, error) {
+	records, err := adapter.NewClient().LookupKeys(meID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]trust.Key, 0, len(records))
+	for _, r := range records {
+		keys = append(keys, trust.Key{MeID: meID, PublicKey: r.PublicKey, Source: trust.SourceMeServer, RetiredAt: r.RetiredAt, RevokedAt: r.RevokedAt})
+	}
+	return keys, nil
+}
+
+func init() {
+	trustAddCmd.Flags().StringVar(&trustKey, "key", "", "Hex-encoded Ed25519 public key to pin")
+	trustAddCmd.Flags().StringVar(&trustName, "name", "", "Optional label, e.g. the reviewer or organization name")
+	trustAddCmd.Flags().StringSliceVar(&trustRoles, "role", nil, "Authorize the signer to co-sign as reviewer and/or organization")
+	trustRmCmd.Flags().StringVar(&trustKey, "key", "", "Only remove this public key")
+
+	trustCmd.AddCommand(trustAddCmd)
+	trustCmd.AddCommand(trustRmCmd)
+	trustCmd.AddCommand(trustLsCmd)
+	rootCmd.AddCommand(trustCmd)
+}
@@ -8,6 +8,8 @@ require (
 	github.com/pterm/pterm v0.12.82
 	github.com/schollz/progressbar/v3 v3.19.0
 	github.com/spf13/cobra v1.7.0
+	golang.org/x/crypto v0.38.0
+	golang.org/x/term v0.32.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -28,6 +30,5 @@ require (
 	github.com/spf13/pflag v1.0.5 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/term v0.32.0 // indirect
 	golang.org/x/text v0.26.0 // indirect
 )
@@ -14,7 +14,8 @@ type Profile struct {
 	AccessToken string `json:"access_token"`
 	PublicKey   string `json:"public_key"`
 	MeID        string `json:"me_id"`
-	SecretKey   string `json:"secret_key"`
+	SecretKey   string `json:"secret_key,omitempty"` // 旧版明文私钥，仅用于迁移到加密密钥文件 (见 GetKeyPath)
+	SecretCmd   string `json:"secret_cmd,omitempty"` // 外部密钥命令，用于 {{secret.cmd.NAME}}
 }
 
 // CLIConfig 根配置结构
@@ -32,6 +33,30 @@ func GetConfigPath() string {
 	return path
 }
 
+// GetCacheDir 返回响应缓存目录 (~/.runly/cache)
+func GetCacheDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".runly", "cache")
+}
+
+// GetVaultPath 返回本地加密保险库路径 (~/.runly/vault.json)
+func GetVaultPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".runly", "vault.json")
+}
+
+// GetTrustPath 返回信任库路径 (~/.runly/trust.json)
+func GetTrustPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".runly", "trust.json")
+}
+
+// GetKeyPath 返回 Profile 的加密签名私钥路径 (~/.runly/keys/<profile>.key)
+func GetKeyPath(profile string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".runly", "keys", profile+".key")
+}
+
 // Exists 检查配置文件是否存在
 func Exists() bool {
 	// 修正：统一使用 GetConfigPath 获取的路径，确保检查的是同一个 .json 文件
@@ -71,14 +96,18 @@ func LoadConfig() (*CLIConfig, error) {
 	return &cfg, nil
 }
 
-// SaveConfig 持久化配置到磁盘
+// SaveConfig 持久化配置到磁盘 (0600，配置中包含 AccessToken)
 func (c *CLIConfig) SaveConfig() error {
 	path := GetConfigPath()
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0644)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile 不会修改已存在文件的权限，旧版以 0644 创建的配置在此收紧
+	return os.Chmod(path, 0600)
 }
 
 // GetActive 获取当前活跃的环境配置
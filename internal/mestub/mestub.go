@@ -0,0 +1,288 @@
+// Package mestub 本地 Me 身份服务替身：实现公钥登记、查询、轮换与吊销接口，
+// 按协议校验持有证明与声明签名，用于测试与离线联调 (状态只保存在内存中)
+package mestub
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChallengeTTL 挑战的有效期
+const ChallengeTTL = 5 * time.Minute
+
+// challenge 已签发、尚未使用的挑战
+type challenge struct {
+	nonce     string
+	publicKey string
+	expiresAt time.Time
+}
+
+// Server 内存中的 Me 身份服务
+type Server struct {
+	mu         sync.Mutex
+	challenges map[string]challenge
+	keys       map[string][]string  // MeID -> 按登记顺序的公钥，最后一把为当前密钥
+	revoked    map[string]time.Time // 公钥 -> 吊销时间
+	retired    map[string]time.Time // 公钥 -> 轮换时间 (服务端登记轮换的时间，而非声明中的时间)
+	now        func() time.Time
+
+	// Requests 收到的全部请求载荷 (按路径)，测试用于断言私钥从未离开本机
+	Requests map[string][]map[string]interface{}
+}
+
+// New 创建空的身份服务
+func New() *Server {
+	return &Server{
+		challenges: make(map[string]challenge),
+		keys:       make(map[string][]string),
+		revoked:    make(map[string]time.Time),
+		retired:    make(map[string]time.Time),
+		now:        time.Now,
+		Requests:   make(map[string][]map[string]interface{}),
+	}
+}
+
+// Keys 返回 MeID 登记过的全部公钥
+func (s *Server) Keys(meID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.keys[meID]...)
+}
+
+// ServeHTTP 实现 http.Handler，请求与响应沿用 RequestClient 的 {header, payload} / {status, data} 信封
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Payload map[string]interface{} `json:"payload"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "invalid request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Requests[r.URL.Path] = append(s.Requests[r.URL.Path], req.Payload)
+
+	p := payload(req.Payload)
+	var (
+		data map[string]interface{}
+		err  error
+	)
+	switch r.URL.Path {
+	case "/v1/me/keys/challenge":
+		data, err = s.challenge(p)
+	case "/v1/me/keys/register":
+		data, err = s.register(p)
+	case "/v1/me/keys/lookup":
+		data, err = s.lookup(p)
+	case "/v1/me/keys/rotate":
+		data, err = s.rotate(p)
+	case "/v1/me/keys/revoke":
+		data, err = s.revoke(p)
+	default:
+		err = fmt.Errorf("not found: %s", r.URL.Path)
+	}
+	if err != nil {
+		writeError(w, err.Error())
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "data": data})
+}
+
+// challenge 为待登记的公钥签发一次性挑战
+func (s *Server) challenge(p payload) (map[string]interface{}, error) {
+	pub := p.str("public_key")
+	if _, err := decodeKey(pub); err != nil {
+		return nil, err
+	}
+	id, nonce := randomHex(8), randomHex(16)
+	expires := s.now().Add(ChallengeTTL).UTC().Truncate(time.Second)
+	s.challenges[id] = challenge{nonce: nonce, publicKey: pub, expiresAt: expires}
+	return map[string]interface{}{"challenge_id": id, "nonce": nonce, "expires_at": expires.Format(time.RFC3339)}, nil
+}
+
+// register 校验持有证明后登记公钥；挑战只能使用一次，请求中出现私钥时直接拒绝
+func (s *Server) register(p payload) (map[string]interface{}, error) {
+	if _, ok := p["secret_key"]; ok {
+		return nil, fmt.Errorf("secret key must not be sent to the Me server")
+	}
+	id, pubHex := p.str("challenge_id"), p.str("public_key")
+	ch, ok := s.challenges[id]
+	delete(s.challenges, id)
+	if !ok || ch.publicKey != pubHex {
+		return nil, fmt.Errorf("unknown challenge")
+	}
+	if s.now().After(ch.expiresAt) {
+		return nil, fmt.Errorf("challenge expired")
+	}
+	msg := fmt.Sprintf("runly-me-key-proof/v1\n%s\n%s\n%s", id, ch.nonce, pubHex)
+	if err := verify(pubHex, []byte(msg), p.str("signature")); err != nil {
+		return nil, fmt.Errorf("invalid proof of possession")
+	}
+
+	meID := "me_0x" + pubHex[:12]
+	if name := p.str("username"); name != "" {
+		meID = "me_" + name
+	}
+	// 重复登记同一公钥是幂等的；已有密钥的 MeID 只能经由当前密钥签名的轮换声明追加新密钥，
+	// 否则任何人都能以同名登记并接管该身份
+	if owner := s.owner(pubHex); owner != "" {
+		if owner != meID {
+			return nil, fmt.Errorf("public key is already registered")
+		}
+		return map[string]interface{}{"me_id": meID}, nil
+	}
+	if len(s.keys[meID]) > 0 {
+		return nil, fmt.Errorf("%s is already registered; add keys through rotation", meID)
+	}
+	s.keys[meID] = []string{pubHex}
+	return map[string]interface{}{"me_id": meID}, nil
+}
+
+// lookup 返回 MeID 登记过的公钥：public_keys 只含当前密钥，轮换下来的公钥附带轮换时间列入 retired_keys，
+// 已吊销的公钥附带吊销时间 (及轮换时间) 列入 revoked_keys
+func (s *Server) lookup(p payload) (map[string]interface{}, error) {
+	keys := s.keys[p.str("me_id")]
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("not found")
+	}
+	active := []string{}
+	retired := []map[string]string{}
+	revoked := []map[string]string{}
+	for _, k := range keys {
+		entry := map[string]string{"public_key": k}
+		if at, ok := s.retired[k]; ok {
+			entry["retired_at"] = at.Format(time.RFC3339)
+		}
+		switch at, ok := s.revoked[k]; {
+		case ok:
+			entry["revoked_at"] = at.Format(time.RFC3339)
+			revoked = append(revoked, entry)
+		case entry["retired_at"] != "":
+			retired = append(retired, entry)
+		default:
+			active = append(active, k)
+		}
+	}
+	return map[string]interface{}{"public_keys": active, "retired_keys": retired, "revoked_keys": revoked}, nil
+}
+
+// rotate 登记轮换：声明须由 MeID 的当前密钥签名，并附带新密钥对同一声明的持有证明
+func (s *Server) rotate(p payload) (map[string]interface{}, error) {
+	raw := p.str("statement")
+	var st map[string]string
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		return nil, fmt.Errorf("invalid statement")
+	}
+	meID := st["me_id"]
+	if current := s.current(meID); current == "" || current != st["old_key"] {
+		return nil, fmt.Errorf("old key is not the current key of %s", meID)
+	}
+	if err := verify(st["old_key"], []byte(raw), p.str("signature")); err != nil {
+		return nil, fmt.Errorf("invalid rotation signature")
+	}
+	if err := verify(st["new_key"], []byte(raw), p.str("proof")); err != nil {
+		return nil, fmt.Errorf("invalid proof of possession")
+	}
+	if s.owner(st["new_key"]) != "" {
+		return nil, fmt.Errorf("public key is already registered")
+	}
+	s.keys[meID] = append(s.keys[meID], st["new_key"])
+	s.retired[st["old_key"]] = s.now().UTC().Truncate(time.Second)
+	return map[string]interface{}{"me_id": meID}, nil
+}
+
+// revoke 登记吊销：声明须由 MeID 的当前密钥签名，当前密钥本身不能吊销
+func (s *Server) revoke(p payload) (map[string]interface{}, error) {
+	raw := p.str("statement")
+	var st map[string]string
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		return nil, fmt.Errorf("invalid statement")
+	}
+	meID, target := st["me_id"], st["public_key"]
+	current := s.current(meID)
+	if current == "" || !contains(s.keys[meID], target) || target == current {
+		return nil, fmt.Errorf("key %s cannot be revoked for %s", target, meID)
+	}
+	if err := verify(current, []byte(raw), p.str("signature")); err != nil {
+		return nil, fmt.Errorf("invalid revocation signature")
+	}
+	at, err := time.Parse(time.RFC3339, st["revoked_at"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid revoked_at")
+	}
+	s.revoked[target] = at
+	return map[string]interface{}{}, nil
+}
+
+// current MeID 的当前密钥
+func (s *Server) current(meID string) string {
+	keys := s.keys[meID]
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[len(keys)-1]
+}
+
+// owner 登记过该公钥的 MeID
+func (s *Server) owner(pubHex string) string {
+	for meID, keys := range s.keys {
+		if contains(keys, pubHex) {
+			return meID
+		}
+	}
+	return ""
+}
+
+type payload map[string]interface{}
+
+func (p payload) str(key string) string {
+	v, _ := p[key].(string)
+	return v
+}
+
+func decodeKey(pubHex string) (ed25519.PublicKey, error) {
+	raw, err := hex.DecodeString(pubHex)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key %q", pubHex)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+func verify(pubHex string, msg []byte, sigHex string) error {
+	pub, err := decodeKey(pubHex)
+	if err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil || !ed25519.Verify(pub, msg, sig) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func contains(list []string, v string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeError(w http.ResponseWriter, msg string) {
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "error", "message": msg})
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -35,7 +36,7 @@ func PrintFooter(key string) {
 
 // PrintStep 打印带图标的多语言执行步骤
 func PrintStep(key string, args ...interface{}) {
-	message := fmt.Sprintf(i18n.T(key), args...)
+	message := format(key, args...)
 	pterm.Info.Println(message)
 }
 
@@ -46,16 +47,25 @@ func PrintSuccess(key string) {
 
 // PrintError 打印多语言错误反馈
 func PrintError(key string, args ...interface{}) {
-	message := fmt.Sprintf(i18n.T(key), args...)
+	message := format(key, args...)
 	pterm.Error.Println(message)
 }
 
 // PrintWarning 打印多语言警告
 func PrintWarning(key string, args ...interface{}) {
-	message := fmt.Sprintf(i18n.T(key), args...)
+	message := format(key, args...)
 	pterm.Warning.Println(message)
 }
 
+// format 翻译并格式化消息；文案本身不含占位符时 (如 common.failure)，将参数追加在冒号之后
+func format(key string, args ...interface{}) string {
+	text := i18n.T(key)
+	if len(args) > 0 && !strings.Contains(text, "%") {
+		return text + ": " + fmt.Sprint(args...)
+	}
+	return fmt.Sprintf(text, args...)
+}
+
 // StartLoading 启动一个多语言感知的加载动画
 func StartLoading(key string) *spinner.Spinner {
 	s := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
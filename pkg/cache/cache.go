@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry 一条缓存的服务端响应
+type Entry struct {
+	Key       string          `json:"key"`
+	Kind      string          `json:"kind"` // skill | knowledge
+	Ref       string          `json:"ref"`
+	Method    string          `json:"method"`
+	Endpoint  string          `json:"endpoint"`
+	BodyHash  string          `json:"body_hash"`
+	CreatedAt time.Time       `json:"created_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	Response  json.RawMessage `json:"response"`
+
+	File    string `json:"-"` // 记录所在的缓存文件，由 List 填充
+	corrupt bool   // 文件无法解析或缓存键不是 SHA-256 摘要，只能清理
+}
+
+// Expired 判断缓存是否已过期
+func (e *Entry) Expired(now time.Time) bool {
+	return now.After(e.ExpiresAt)
+}
+
+// Store 基于目录的磁盘缓存，每条记录一个 JSON 文件
+type Store struct {
+	Dir string
+}
+
+// NewStore 创建指向指定目录的缓存
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Key 由渲染后的请求 (方法、地址、请求体摘要) 计算缓存键
+// 请求头不参与计算，避免密钥进入缓存索引
+func Key(method, endpoint string, body []byte) (key, bodyHash string) {
+	bh := sha256.Sum256(body)
+	bodyHash = hex.EncodeToString(bh[:])
+
+	k := sha256.Sum256([]byte(strings.ToUpper(method) + " " + endpoint + " " + bodyHash))
+	return hex.EncodeToString(k[:]), bodyHash
+}
+
+// ValidKey 判断缓存键是否为 Key 生成的十六进制 SHA-256 摘要；其他值可能借文件名逃逸出缓存目录
+func ValidKey(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil && strings.ToLower(key) == key
+}
+
+// Get 读取未过期的缓存记录，文件内记录的缓存键必须与请求的缓存键一致
+func (s *Store) Get(key string) (*Entry, bool) {
+	if !ValidKey(key) {
+		return nil, false
+	}
+	data, err := os.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key || e.Expired(time.Now()) {
+		return nil, false
+	}
+	return &e, true
+}
+
+// Put 写入一条缓存记录
+func (s *Store) Put(e *Entry) error {
+	if !ValidKey(e.Key) {
+		return fmt.Errorf("invalid cache key %q", e.Key)
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(e.Key), data, 0600)
+}
+
+// List 列出全部有效的缓存记录 (包含已过期的)，按创建时间倒序
+func (s *Store) List() ([]*Entry, error) {
+	all, err := s.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, e := range all {
+		if !e.corrupt {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+	return entries, nil
+}
+
+// scan 读取缓存目录中的全部记录文件；无法解析或缓存键无效的记录标记为损坏
+func (s *Store) scan() ([]*Entry, error) {
+	files, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		e := &Entry{}
+		if json.Unmarshal(data, e) != nil || !ValidKey(e.Key) {
+			e = &Entry{corrupt: true}
+		}
+		e.File = f
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Clear 删除缓存记录，expiredOnly 为 true 时仅清理过期项与损坏项，返回删除数量
+// 删除的是扫描到的文件本身，而不是按文件内容中的缓存键拼出的路径
+func (s *Store) Clear(expiredOnly bool) (int, error) {
+	entries, err := s.scan()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	removed := 0
+	for _, e := range entries {
+		if expiredOnly && !e.corrupt && !e.Expired(now) {
+			continue
+		}
+		if err := os.Remove(e.File); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.Dir, key+".json")
+}
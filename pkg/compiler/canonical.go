@@ -0,0 +1,117 @@
+package compiler
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/pkg/jcs"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Canonicalize 生成签名所用的规范字节序列：签名文档模型经 RFC 8785 (JCS) 序列化
+//
+// 签名文档模型与 Go 结构体布局无关，其他语言的验证器可按以下规则复现：
+//   - 对象键使用 .runly 文件中的字段名 (即 YAML 键名)
+//   - 协议定义的字段取值为空 (null、""、0、false、{}、[]) 时省略，因此新增可选字段不影响既有签名
+//   - 自由格式的映射 (config、schema、contract.request 等) 与数组原样保留，不做省略
+//   - 时间戳统一为 UTC 的 RFC 3339 字符串 (小数秒仅在非零时输出)
+//   - security.signature 与 security.signatures 不参与签名，追加联署签名不改变内容摘要
+func Canonicalize(proto *protocol.RunlyProtocol) ([]byte, error) {
+	doc, _ := document(reflect.ValueOf(proto))
+	if m, ok := doc.(map[string]interface{}); ok {
+		if sec, ok := m["security"].(map[string]interface{}); ok {
+			delete(sec, "signature")
+			delete(sec, "signatures")
+			if len(sec) == 0 {
+				delete(m, "security")
+			}
+		}
+	}
+	return jcs.Marshal(doc)
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// document 将值转换为通用 JSON 值；第二个返回值表示该值是否为空，仅结构体字段据此省略，
+// 映射成员与数组元素始终保留
+func document(v reflect.Value) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, true
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, true
+		}
+		return document(v.Elem())
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			t := v.Interface().(time.Time)
+			if t.IsZero() {
+				return "", true
+			}
+			return t.UTC().Format(time.RFC3339Nano), false
+		}
+		obj := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name := yamlName(field)
+			if name == "" {
+				continue
+			}
+			val, empty := document(v.Field(i))
+			if !empty {
+				obj[name] = val
+			}
+		}
+		return obj, len(obj) == 0
+
+	case reflect.Map:
+		obj := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			val, _ := document(iter.Value())
+			obj[fmt.Sprint(iter.Key().Interface())] = val
+		}
+		return obj, len(obj) == 0
+
+	case reflect.Slice, reflect.Array:
+		arr := make([]interface{}, v.Len())
+		for i := range arr {
+			arr[i], _ = document(v.Index(i))
+		}
+		return arr, len(arr) == 0
+
+	case reflect.String:
+		return v.String(), v.String() == ""
+	case reflect.Bool:
+		return v.Bool(), !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), v.Float() == 0
+	}
+	return fmt.Sprint(v.Interface()), false
+}
+
+// yamlName 返回结构体字段在 .runly 文件中的键名，不参与序列化的字段返回空字符串
+func yamlName(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		return ""
+	}
+	tag := f.Tag.Get("yaml")
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return strings.ToLower(f.Name)
+	}
+	return name
+}
@@ -0,0 +1,42 @@
+package compiler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/originbeat-inc/runly-cli/pkg/crypto"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// 规范化结果即签名对象：文档模型 (字段名、省略规则、时间格式) 的任何意外变化都会使存量签名失效，
+// 须与 testdata/golden.json 逐字节一致。有意修改规范化规则时需同步更新夹具与版本说明
+func TestCanonicalizeGolden(t *testing.T) {
+	proto, err := protocol.Load(filepath.Join("testdata", "golden.runly"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "golden.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Canonicalize(proto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("canonical form changed\ngot  %s\nwant %s", got, want)
+	}
+
+	digest, err := Digest(proto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != crypto.CalculateHash(want) {
+		t.Errorf("digest = %s, want sha256 of golden.json", digest)
+	}
+	if proto.Security.Signature == "" || len(proto.Security.Signatures) != 1 {
+		t.Error("Digest must restore the signature fields")
+	}
+}
@@ -3,41 +3,78 @@ package compiler
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/pkg/crypto"
 	"github.com/originbeat-inc/runly-cli/pkg/protocol"
 )
 
+// CanonicalJCS 当前的签名规范化方案：RFC 8785 JSON Canonicalization Scheme
+// security.canonicalization 缺省表示旧版方案 (直接 json.Marshal 结构体)，仅用于验证存量资产
+const CanonicalJCS = "JCS"
+
 // BuildArtifact 对协议执行标准化分发处理
 func BuildArtifact(proto *protocol.RunlyProtocol, privateKeyHex string) (string, error) {
-	// 1. 签名预处理：清空签名位，设定算法
+	// 1. 签名预处理：清空签名位，设定算法与规范化方案
 	proto.Security.Signature = ""
+	proto.Security.Signatures = nil
 	proto.Security.HashAlgo = "SHA-256"
+	proto.Security.Canonicalization = CanonicalJCS
 
-	// 2. 确定性标准化 (Canonicalization)
-	// 使用有序 JSON 序列化确保不同环境下哈希计算结果的一致性
-	standardizedData, err := json.Marshal(proto)
+	// 2. 确定性标准化 (Canonicalization) 并计算内容摘要
+	contentHash, err := Digest(proto)
 	if err != nil {
-		return "", fmt.Errorf(i18n.T("errors.yaml_unmarshal_fail"), err)
+		return "", err
 	}
 
-	// 3. 计算内容摘要
-	contentHash := crypto.CalculateHash(standardizedData)
-
-	// 4. 执行数字签名（背书）
+	// 3. 执行数字签名（背书）：签名对象为十六进制摘要字符串
 	signature, err := crypto.Sign(privateKeyHex, []byte(contentHash))
 	if err != nil {
 		// 返回多语言错误：未找到签名私钥。请使用 'runly-cli keys generate' 创建身份
 		return "", fmt.Errorf(i18n.T("errors.no_key"), err)
 	}
 
-	// 5. 注入指纹
+	// 4. 注入指纹，并以 creator 角色记录带元数据的多方签名 (重新构建后原有联署签名失效)
 	proto.Security.Signature = signature
+	creatorSig, err := NewSignature(contentHash, protocol.RoleCreator, proto.Manifest.Creator.MeID, proto.Manifest.Creator.PubKey, privateKeyHex, time.Now())
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("errors.no_key"), err)
+	}
+	proto.Security.Signatures = []protocol.Signature{creatorSig}
 
 	return contentHash, nil
 }
 
+// Digest 按 security.canonicalization 声明的方案计算资产内容摘要 (SHA-256 十六进制)，签名位不参与计算
+func Digest(proto *protocol.RunlyProtocol) (string, error) {
+	storedSignature, storedSignatures := proto.Security.Signature, proto.Security.Signatures
+	proto.Security.Signature, proto.Security.Signatures = "", nil
+	defer func() { proto.Security.Signature, proto.Security.Signatures = storedSignature, storedSignatures }()
+
+	var data []byte
+	var err error
+	switch proto.Security.Canonicalization {
+	case CanonicalJCS:
+		data, err = Canonicalize(proto)
+	case "":
+		// 旧版方案：依赖结构体字段顺序与 time.Time 格式，仅为验证存量签名保留
+		data, err = json.Marshal(proto)
+	default:
+		// 🔏 不支持的签名规范化方案
+		return "", fmt.Errorf(i18n.T("errors.canonicalization_unsupported"), proto.Security.Canonicalization)
+	}
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("errors.yaml_unmarshal_fail"), err)
+	}
+	return crypto.CalculateHash(data), nil
+}
+
+// IsLegacy 判断资产是否使用旧版规范化方案签名，建议重新 build 以迁移到 JCS
+func IsLegacy(proto *protocol.RunlyProtocol) bool {
+	return proto.Security.Signature != "" && proto.Security.Canonicalization == ""
+}
+
 // VerifyIntegrity 验证协议资产的完整性与身份真实性
 func VerifyIntegrity(proto *protocol.RunlyProtocol) (bool, error) {
 	storedSignature := proto.Security.Signature
@@ -49,13 +86,11 @@ func VerifyIntegrity(proto *protocol.RunlyProtocol) (bool, error) {
 		return false, fmt.Errorf(i18n.T("errors.no_sig"))
 	}
 
-	// 还原计算态：清空签名位
-	proto.Security.Signature = ""
-	standardizedData, _ := json.Marshal(proto)
-	currentHash := crypto.CalculateHash(standardizedData)
-
-	// 恢复原始对象
-	proto.Security.Signature = storedSignature
+	// 还原计算态并按签名时的规范化方案重算摘要
+	currentHash, err := Digest(proto)
+	if err != nil {
+		return false, err
+	}
 
 	// 校验数字签名
 	isValid, err := crypto.Verify(pubKey, []byte(currentHash), storedSignature)
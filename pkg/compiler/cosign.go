@@ -0,0 +1,79 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/crypto"
+	"github.com/originbeat-inc/runly-cli/pkg/jcs"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// AlgoEd25519 多方签名当前唯一支持的算法
+const AlgoEd25519 = "Ed25519"
+
+// SignaturePayload 多方签名的签名对象：内容摘要与签名元数据的 JCS 序列化，
+// 签名者、角色与时间因此同样受签名保护，无法被替换
+func SignaturePayload(digest string, sig *protocol.Signature) ([]byte, error) {
+	return jcs.Marshal(map[string]interface{}{
+		"digest":    digest,
+		"signer_id": sig.SignerID,
+		"key_id":    strings.ToLower(sig.KeyID),
+		"algorithm": sig.Algorithm,
+		"role":      sig.Role,
+		"signed_at": sig.SignedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// NewSignature 以指定角色对内容摘要签名，私钥与声明的公钥不匹配时返回错误
+func NewSignature(digest, role, meID, publicKeyHex, privateKeyHex string, at time.Time) (protocol.Signature, error) {
+	sig := protocol.Signature{
+		SignerID:  meID,
+		KeyID:     strings.ToLower(publicKeyHex),
+		Algorithm: AlgoEd25519,
+		Role:      role,
+		SignedAt:  at.UTC().Truncate(time.Second),
+	}
+	payload, err := SignaturePayload(digest, &sig)
+	if err != nil {
+		return sig, err
+	}
+	if sig.Value, err = crypto.Sign(privateKeyHex, payload); err != nil {
+		return sig, err
+	}
+	if ok, _ := crypto.Verify(sig.KeyID, payload, sig.Value); !ok {
+		// 🔑 签名私钥与当前身份的公钥不匹配
+		return sig, fmt.Errorf(i18n.T("errors.cosign_key_mismatch"), meID)
+	}
+	return sig, nil
+}
+
+// VerifySignature 验证一方签名与内容摘要匹配
+func VerifySignature(digest string, sig *protocol.Signature) error {
+	if sig.Algorithm != AlgoEd25519 {
+		// 🔏 不支持的签名算法
+		return fmt.Errorf(i18n.T("errors.cosign_algorithm"), sig.Algorithm)
+	}
+	payload, err := SignaturePayload(digest, sig)
+	if err != nil {
+		return err
+	}
+	if ok, err := crypto.Verify(sig.KeyID, payload, sig.Value); err != nil || !ok {
+		// 🛡️ 签名与资产内容不匹配
+		return fmt.Errorf(i18n.T("errors.cosign_invalid"), sig.Role, sig.SignerID)
+	}
+	return nil
+}
+
+// AttachSignature 追加签名，同一签名者以同一角色重复签名时替换原签名
+func AttachSignature(sigs []protocol.Signature, sig protocol.Signature) []protocol.Signature {
+	for i, existing := range sigs {
+		if existing.SignerID == sig.SignerID && existing.Role == sig.Role {
+			sigs[i] = sig
+			return sigs
+		}
+	}
+	return append(sigs, sig)
+}
@@ -0,0 +1,72 @@
+package compiler
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"gopkg.in/yaml.v3"
+)
+
+// DetachedExt 分离签名文件扩展名，与资产同目录存放 (dist.runly → dist.runly.sig)
+const DetachedExt = ".sig"
+
+// Detached 分离签名文件：联署签名不写回资产，资产文件保持字节不变，便于多方并行审核
+type Detached struct {
+	URN              string               `yaml:"urn"`
+	Version          string               `yaml:"version"`
+	Canonicalization string               `yaml:"canonicalization,omitempty"`
+	Digest           string               `yaml:"digest"` // 签名对应的内容摘要
+	Signatures       []protocol.Signature `yaml:"signatures"`
+}
+
+// DetachedPath 资产对应的分离签名文件路径
+func DetachedPath(assetPath string) string {
+	return assetPath + DetachedExt
+}
+
+// NewDetached 为资产创建空的分离签名文件
+func NewDetached(proto *protocol.RunlyProtocol) (*Detached, error) {
+	digest, err := Digest(proto)
+	if err != nil {
+		return nil, err
+	}
+	return &Detached{
+		URN:              proto.Manifest.URN,
+		Version:          proto.Manifest.Version,
+		Canonicalization: proto.Security.Canonicalization,
+		Digest:           digest,
+	}, nil
+}
+
+// LoadDetached 读取分离签名文件
+func LoadDetached(path string) (*Detached, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d := &Detached{}
+	if err := yaml.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.yaml_unmarshal_fail"), err)
+	}
+	return d, nil
+}
+
+// Save 写出分离签名文件
+func (d *Detached) Save(path string) error {
+	data, err := yaml.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Matches 判断分离签名是否属于该资产的当前内容
+func (d *Detached) Matches(digest string) error {
+	if d.Digest != digest {
+		// ✍️ 分离签名文件对应的是另一份内容
+		return fmt.Errorf(i18n.T("errors.detached_mismatch"), d.URN, d.Version)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Metric 单一维度的覆盖率统计
+type Metric struct {
+	Total   int     `json:"total"`
+	Covered int     `json:"covered"`
+	Percent float64 `json:"percent"`
+}
+
+// Edge 拓扑中的一条静态跳转边 (on_success / on_failure)
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// Rule LOGIC_GATE 节点中的一条规则
+type Rule struct {
+	NodeID    string `json:"node_id"`
+	Index     int    `json:"index"`
+	Condition string `json:"condition"`
+	Next      string `json:"next"`
+}
+
+// Report 基于一组运行轨迹计算出的拓扑覆盖率报告
+type Report struct {
+	URN            string   `json:"urn"`
+	Traces         int      `json:"traces"`
+	Skipped        int      `json:"skipped"` // URN 或版本不匹配而被忽略的轨迹数
+	Nodes          Metric   `json:"nodes"`
+	Edges          Metric   `json:"edges"`
+	Rules          Metric   `json:"rules"`
+	UncoveredNodes []string `json:"uncovered_nodes"`
+	UncoveredEdges []Edge   `json:"uncovered_edges"`
+	UncoveredRules []Rule   `json:"uncovered_rules"`
+}
+
+// Compute 将轨迹中的命中记录与协议拓扑进行比对；只合并同一 URN 与版本的轨迹，被预算中止的节点不计入
+func Compute(proto *protocol.RunlyProtocol, traces []*executor.Trace) *Report {
+	hitNodes := make(map[string]bool)
+	hitEdges := make(map[Edge]bool)
+	hitRules := make(map[string]bool)
+
+	merged, skipped := 0, 0
+	for _, t := range traces {
+		// 其他资产或其他版本的轨迹与当前拓扑无关，不参与合并
+		if !t.Matches(proto.Manifest.URN, proto.Manifest.Version) {
+			skipped++
+			continue
+		}
+		merged++
+		for _, s := range t.Steps {
+			if s.Aborted {
+				continue
+			}
+			hitNodes[s.NodeID] = true
+			switch s.Edge {
+			case executor.EdgeSuccess, executor.EdgeFailure:
+				hitEdges[Edge{From: s.NodeID, To: s.Next, Kind: s.Edge}] = true
+			case executor.EdgeRule:
+				hitRules[ruleKey(s.NodeID, s.Rule)] = true
+			}
+		}
+	}
+
+	report := &Report{
+		URN:            proto.Manifest.URN,
+		Traces:         merged,
+		Skipped:        skipped,
+		UncoveredNodes: []string{},
+		UncoveredEdges: []Edge{},
+		UncoveredRules: []Rule{},
+	}
+
+	for _, n := range proto.Topology.Nodes {
+		report.Nodes.Total++
+		if hitNodes[n.ID] {
+			report.Nodes.Covered++
+		} else {
+			report.UncoveredNodes = append(report.UncoveredNodes, n.ID)
+		}
+
+		for _, e := range staticEdges(n) {
+			report.Edges.Total++
+			if hitEdges[e] {
+				report.Edges.Covered++
+			} else {
+				report.UncoveredEdges = append(report.UncoveredEdges, e)
+			}
+		}
+
+		if n.Type != "LOGIC_GATE" {
+			continue
+		}
+		for i, r := range n.Rules {
+			report.Rules.Total++
+			if hitRules[ruleKey(n.ID, i)] {
+				report.Rules.Covered++
+			} else {
+				report.UncoveredRules = append(report.UncoveredRules, Rule{
+					NodeID: n.ID, Index: i, Condition: r.Condition, Next: r.Next,
+				})
+			}
+		}
+	}
+
+	report.Nodes.Percent = percent(report.Nodes)
+	report.Edges.Percent = percent(report.Edges)
+	report.Rules.Percent = percent(report.Rules)
+
+	sort.Strings(report.UncoveredNodes)
+	return report
+}
+
+// Below 返回低于阈值的覆盖维度名称，空切片表示全部达标
+func (r *Report) Below(threshold float64) []string {
+	var failed []string
+	for _, m := range []struct {
+		name string
+		m    Metric
+	}{{"nodes", r.Nodes}, {"edges", r.Edges}, {"rules", r.Rules}} {
+		if m.m.Total > 0 && m.m.Percent < threshold {
+			failed = append(failed, m.name)
+		}
+	}
+	return failed
+}
+
+// staticEdges 列出节点声明的 on_success / on_failure 跳转
+func staticEdges(n protocol.Node) []Edge {
+	var edges []Edge
+	if n.OnSuccess != "" {
+		edges = append(edges, Edge{From: n.ID, To: n.OnSuccess, Kind: executor.EdgeSuccess})
+	}
+	if n.OnFailure != "" {
+		edges = append(edges, Edge{From: n.ID, To: n.OnFailure, Kind: executor.EdgeFailure})
+	}
+	return edges
+}
+
+func ruleKey(nodeID string, idx int) string {
+	return fmt.Sprintf("%s#%d", nodeID, idx)
+}
+
+func percent(m Metric) float64 {
+	if m.Total == 0 {
+		return 100
+	}
+	return float64(m.Covered) * 100 / float64(m.Total)
+}
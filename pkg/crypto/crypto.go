@@ -32,6 +32,10 @@ func Verify(publicKeyHex string, message []byte, signatureHex string) (bool, err
 	if err != nil {
 		return false, fmt.Errorf("invalid public key: %w", err)
 	}
+	// 长度不符时 ed25519.Verify 会 panic (如 creator.pub_key 为空)
+	if len(pubBytes) != ed25519.PublicKeySize {
+		return false, fmt.Errorf("invalid public key length: %d", len(pubBytes))
+	}
 
 	sigBytes, err := hex.DecodeString(signatureHex)
 	if err != nil {
@@ -0,0 +1,143 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt 参数：N=2^15, r=8, p=1 (交互式场景推荐值)
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// 打开密文时接受的 scrypt 参数上限：参数取自文件，不设上限时伪造的文件可耗尽内存与 CPU
+const (
+	maxScryptN   = 1 << 20
+	maxScryptR   = 32
+	maxScryptP   = 16
+	maxScryptMem = 256 << 20 // scrypt 内存占用约为 128*N*r 字节
+)
+
+// gcmNonceSize AES-GCM 标准 nonce 长度
+const gcmNonceSize = 12
+
+// ErrWrongPassphrase 口令错误或密文被篡改
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+// SealedBox 口令加密后的数据封装 (scrypt 派生密钥 + AES-256-GCM)
+type SealedBox struct {
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       string `json:"salt"`
+	Cipher     string `json:"cipher"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// SealWithPassphrase 使用口令加密数据
+func SealWithPassphrase(plaintext, passphrase []byte) (*SealedBox, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, salt, scryptN, scryptR, scryptP)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &SealedBox{
+		KDF:        "scrypt",
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+		Salt:       hex.EncodeToString(salt),
+		Cipher:     "aes-256-gcm",
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}, nil
+}
+
+// Validate 检查封装格式与参数，不执行密钥派生；来自不可信来源的文件应先校验再解密
+func (box *SealedBox) Validate() error {
+	_, _, _, err := box.decode()
+	return err
+}
+
+// decode 校验参数并解码 salt、nonce 与密文
+func (box *SealedBox) decode() (salt, nonce, ciphertext []byte, err error) {
+	if box == nil {
+		return nil, nil, nil, errors.New("missing sealed box")
+	}
+	if box.KDF != "scrypt" || box.Cipher != "aes-256-gcm" {
+		return nil, nil, nil, fmt.Errorf("unsupported sealed box: %s/%s", box.KDF, box.Cipher)
+	}
+	if box.N <= 1 || box.N > maxScryptN || box.R <= 0 || box.R > maxScryptR || box.P <= 0 || box.P > maxScryptP ||
+		128*box.N*box.R > maxScryptMem {
+		return nil, nil, nil, fmt.Errorf("scrypt parameters out of range: N=%d r=%d p=%d", box.N, box.R, box.P)
+	}
+	if salt, err = hex.DecodeString(box.Salt); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid salt: %w", err)
+	}
+	if nonce, err = hex.DecodeString(box.Nonce); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	if len(nonce) != gcmNonceSize {
+		return nil, nil, nil, fmt.Errorf("invalid nonce length: %d", len(nonce))
+	}
+	if ciphertext, err = hex.DecodeString(box.Ciphertext); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	return salt, nonce, ciphertext, nil
+}
+
+// OpenWithPassphrase 使用口令解密数据
+func OpenWithPassphrase(box *SealedBox, passphrase []byte) ([]byte, error) {
+	salt, nonce, ciphertext, err := box.decode()
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, salt, box.N, box.R, box.P)
+	if err != nil {
+		return nil, err
+	}
+	// gcm.Open 遇到长度不符的 nonce 会 panic
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: %d", len(nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func newGCM(passphrase, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, n, r, p, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,42 @@
+package diff
+
+import "github.com/originbeat-inc/runly-cli/pkg/semver"
+
+// Bump 新版本号相对旧版本号实际的升级级别，未升级 (相同或降级) 时返回 None
+func Bump(old, new semver.Version) Impact {
+	if !old.LessThan(new) {
+		return None
+	}
+	switch {
+	case new.Major != old.Major:
+		return Major
+	case new.Minor != old.Minor:
+		return Minor
+	}
+	return Patch
+}
+
+// Required 变更所要求的最低升级级别
+// 按 semver 约定，0.x 阶段的接口尚不稳定，破坏性变更只需升级次版本号
+func (r *Report) Required(old semver.Version) Impact {
+	if r.Impact == Major && old.Major == 0 {
+		return Minor
+	}
+	return r.Impact
+}
+
+// Satisfies 实际升级级别是否满足要求
+func Satisfies(actual, required Impact) bool {
+	return impactRank[actual] >= impactRank[required]
+}
+
+// Suggest 在旧版本号基础上按级别给出建议的新版本号
+func Suggest(old semver.Version, level Impact) semver.Version {
+	switch level {
+	case Major:
+		return old.IncMajor()
+	case Minor:
+		return old.IncMinor()
+	}
+	return old.IncPatch()
+}
@@ -0,0 +1,518 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+	"github.com/originbeat-inc/runly-cli/pkg/graph"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/originbeat-inc/runly-cli/pkg/textdiff"
+)
+
+// Impact 变更对调用方的影响，对应语义化版本的升级级别
+type Impact string
+
+const (
+	None  Impact = "none"
+	Patch Impact = "patch" // 提示词微调等不改变行为契约的修改
+	Minor Impact = "minor" // 新增节点、调整跳转等向后兼容的行为变化
+	Major Impact = "major" // 输入、产物或计费契约的破坏性变更
+)
+
+var impactRank = map[Impact]int{None: 0, Patch: 1, Minor: 2, Major: 3}
+
+// Max 返回两者中影响更大的一个
+func (i Impact) Max(o Impact) Impact {
+	if impactRank[o] > impactRank[i] {
+		return o
+	}
+	return i
+}
+
+// Kind 变更类型
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change 一条协议级变更
+type Change struct {
+	Section string        `json:"section"` // manifest | inputs | artifacts | knowledge | skills | nodes | edges | commerce
+	Kind    Kind          `json:"kind"`
+	Path    string        `json:"path"` // 如 nodes.write.config.prompt、edges.gate.rule#1
+	Old     string        `json:"old,omitempty"`
+	New     string        `json:"new,omitempty"`
+	Impact  Impact        `json:"impact"`
+	Words   []textdiff.Op `json:"-"`              // 提示词的词级差异
+	Inline  string        `json:"diff,omitempty"` // 词级差异的文本形式：[-删除-]{+新增+}
+}
+
+// Breaking 是否为破坏性变更
+func (c Change) Breaking() bool {
+	return c.Impact == Major
+}
+
+// Report 两个协议版本之间的全部变更，按区块排序、区块内保持声明顺序
+type Report struct {
+	OldVersion string   `json:"old_version"`
+	NewVersion string   `json:"new_version"`
+	Impact     Impact   `json:"impact"` // 全部变更中最高的影响级别，即建议的版本升级级别
+	Changes    []Change `json:"changes"`
+}
+
+// Breaking 返回全部破坏性变更
+func (r *Report) Breaking() []Change {
+	var out []Change
+	for _, c := range r.Changes {
+		if c.Breaking() {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// sections 输出顺序：先调用方可见的契约，再内部实现
+var sections = []string{"manifest", "inputs", "artifacts", "commerce", "knowledge", "skills", "nodes", "edges"}
+
+// promptKeys 节点配置中按词级比较的自由文本字段
+var promptKeys = map[string]bool{"prompt": true, "instruction": true, "query": true}
+
+// refKeys 节点配置中改变资源绑定或数据来源的字段
+var refKeys = map[string]bool{"skill_ref": true, "knowledge_ref": true, "artifact_ref": true, "data_source": true}
+
+// Compare 在协议层面比较两个版本：节点与跳转、提示词、技能与知识库、输入输出契约以及计费设置
+func Compare(old, new *protocol.RunlyProtocol) *Report {
+	c := &comparer{}
+	c.manifest(old.Manifest, new.Manifest)
+	c.inputs(old.Dictionary.Inputs, new.Dictionary.Inputs)
+	c.artifacts(old.Dictionary.Artifacts, new.Dictionary.Artifacts)
+	c.commerce(old.Commerce, new.Commerce)
+	c.knowledge(old.Knowledge, new.Knowledge)
+	c.skills(old.Skills, new.Skills)
+	c.nodes(old.Topology, new.Topology)
+	c.edges(old, new)
+
+	order := make(map[string]int, len(sections))
+	for i, s := range sections {
+		order[s] = i
+	}
+	sort.SliceStable(c.changes, func(i, j int) bool {
+		return order[c.changes[i].Section] < order[c.changes[j].Section]
+	})
+
+	r := &Report{
+		OldVersion: old.Manifest.Version,
+		NewVersion: new.Manifest.Version,
+		Impact:     None,
+		Changes:    c.changes,
+	}
+	for _, ch := range c.changes {
+		r.Impact = r.Impact.Max(ch.Impact)
+	}
+	if r.Changes == nil {
+		r.Changes = []Change{}
+	}
+	return r
+}
+
+type comparer struct {
+	changes []Change
+}
+
+func (c *comparer) add(section string, kind Kind, path string, old, new interface{}, impact Impact) *Change {
+	c.changes = append(c.changes, Change{
+		Section: section,
+		Kind:    kind,
+		Path:    path,
+		Old:     render(old),
+		New:     render(new),
+		Impact:  impact,
+	})
+	return &c.changes[len(c.changes)-1]
+}
+
+// field 比较单个字段，不同时记录为 changed
+func (c *comparer) field(section, path string, old, new interface{}, impact Impact) {
+	if !reflect.DeepEqual(old, new) {
+		c.add(section, Changed, path, old, new, impact)
+	}
+}
+
+func (c *comparer) manifest(old, new protocol.Manifest) {
+	// version / created_at / updated_at 属于发布元数据，不视为协议变更
+	c.field("manifest", "manifest.urn", old.URN, new.URN, Major)
+	c.field("manifest", "manifest.min_runtime", old.MinRuntime, new.MinRuntime, Minor)
+	c.field("manifest", "manifest.title", old.Title, new.Title, Patch)
+	c.field("manifest", "manifest.status", old.Status, new.Status, Patch)
+}
+
+// inputs 输入参数决定调用方的传参方式：删除、改类型、收紧约束或新增必填项都会破坏现有调用
+func (c *comparer) inputs(old, new []protocol.Parameter) {
+	oldMap := make(map[string]protocol.Parameter)
+	for _, p := range old {
+		oldMap[p.Name] = p
+	}
+	newMap := make(map[string]protocol.Parameter)
+	for _, p := range new {
+		newMap[p.Name] = p
+	}
+
+	for _, p := range old {
+		if _, ok := newMap[p.Name]; !ok {
+			c.add("inputs", Removed, "inputs."+p.Name, p.Type, nil, Major)
+		}
+	}
+	for _, p := range new {
+		path := "inputs." + p.Name
+		o, ok := oldMap[p.Name]
+		if !ok {
+			impact := Minor
+			if p.Required && p.Default == nil {
+				impact = Major
+			}
+			c.add("inputs", Added, path, nil, p.Type, impact)
+			continue
+		}
+
+		c.field("inputs", path+".type", o.Type, p.Type, Major)
+		if o.Required != p.Required {
+			impact := Minor
+			if p.Required && p.Default == nil {
+				impact = Major
+			}
+			c.add("inputs", Changed, path+".required", o.Required, p.Required, impact)
+		}
+		if o.Pattern != p.Pattern {
+			impact := Major
+			if p.Pattern == "" {
+				impact = Minor // 取消格式约束只会放宽
+			}
+			c.add("inputs", Changed, path+".pattern", o.Pattern, p.Pattern, impact)
+		}
+		if !reflect.DeepEqual(o.Values, p.Values) {
+			impact := Minor
+			if len(p.Values) > 0 && (len(o.Values) == 0 || !subset(o.Values, p.Values)) {
+				impact = Major // 引入或收窄枚举会拒绝原本合法的取值
+			}
+			c.add("inputs", Changed, path+".values", o.Values, p.Values, impact)
+		}
+		c.field("inputs", path+".default", o.Default, p.Default, Patch)
+	}
+}
+
+// artifacts 产物是调用方消费的输出契约
+func (c *comparer) artifacts(old, new []protocol.Artifact) {
+	oldMap := make(map[string]protocol.Artifact)
+	for _, a := range old {
+		oldMap[a.ID] = a
+	}
+	newIDs := make(map[string]bool)
+	for _, a := range new {
+		newIDs[a.ID] = true
+	}
+
+	for _, a := range old {
+		if !newIDs[a.ID] {
+			c.add("artifacts", Removed, "artifacts."+a.ID, a.Type, nil, Major)
+		}
+	}
+	for _, a := range new {
+		path := "artifacts." + a.ID
+		o, ok := oldMap[a.ID]
+		if !ok {
+			c.add("artifacts", Added, path, nil, a.Type, Minor)
+			continue
+		}
+		c.field("artifacts", path+".type", o.Type, a.Type, Major)
+		c.field("artifacts", path+".schema", o.Schema, a.Schema, Major)
+		c.field("artifacts", path+".description", o.Description, a.Description, Patch)
+	}
+}
+
+// commerce 计费模式与币种变化直接影响使用者，按破坏性变更处理
+func (c *comparer) commerce(old, new protocol.Commerce) {
+	c.field("commerce", "commerce.pricing.mode", old.Pricing.Mode, new.Pricing.Mode, Major)
+	c.field("commerce", "commerce.pricing.currency", old.Pricing.Currency, new.Pricing.Currency, Major)
+	c.field("commerce", "commerce.pricing.amount", old.Pricing.Amount, new.Pricing.Amount, Minor)
+	c.field("commerce", "commerce.royalty.creator_share", old.Royalty.CreatorShare, new.Royalty.CreatorShare, Patch)
+	c.field("commerce", "commerce.royalty.platform_share", old.Royalty.PlatformShare, new.Royalty.PlatformShare, Patch)
+	c.field("commerce", "commerce.settlement.trigger", old.Settlement.Trigger, new.Settlement.Trigger, Patch)
+}
+
+func (c *comparer) knowledge(old, new []protocol.KnowledgeResource) {
+	oldMap := make(map[string]protocol.KnowledgeResource)
+	for _, k := range old {
+		oldMap[k.ID] = k
+	}
+	newIDs := make(map[string]bool)
+	for _, k := range new {
+		newIDs[k.ID] = true
+	}
+
+	for _, k := range old {
+		if !newIDs[k.ID] {
+			c.add("knowledge", Removed, "knowledge."+k.ID, k.ProviderType, nil, Minor)
+		}
+	}
+	for _, k := range new {
+		path := "knowledge." + k.ID
+		o, ok := oldMap[k.ID]
+		if !ok {
+			c.add("knowledge", Added, path, nil, k.ProviderType, Minor)
+			continue
+		}
+		c.field("knowledge", path+".provider_type", o.ProviderType, k.ProviderType, Minor)
+		c.field("knowledge", path+".config.endpoint", o.Config.Endpoint, k.Config.Endpoint, Minor)
+		c.field("knowledge", path+".config.method", o.Config.Method, k.Config.Method, Minor)
+		c.field("knowledge", path+".config.headers", o.Config.Headers, k.Config.Headers, Patch)
+		c.field("knowledge", path+".config.vdb_params", o.Config.VDBParams, k.Config.VDBParams, Patch)
+		c.field("knowledge", path+".injection.target_variable", o.Injection.TargetVariable, k.Injection.TargetVariable, Minor)
+		c.field("knowledge", path+".injection.max_tokens", o.Injection.MaxTokens, k.Injection.MaxTokens, Patch)
+		c.field("knowledge", path+".injection.format", o.Injection.Format, k.Injection.Format, Patch)
+	}
+}
+
+func (c *comparer) skills(old, new []protocol.SkillResource) {
+	oldMap := make(map[string]protocol.SkillResource)
+	for _, s := range old {
+		oldMap[s.ID] = s
+	}
+	newIDs := make(map[string]bool)
+	for _, s := range new {
+		newIDs[s.ID] = true
+	}
+
+	for _, s := range old {
+		if !newIDs[s.ID] {
+			c.add("skills", Removed, "skills."+s.ID, s.Config.Endpoint, nil, Minor)
+		}
+	}
+	for _, s := range new {
+		path := "skills." + s.ID
+		o, ok := oldMap[s.ID]
+		if !ok {
+			c.add("skills", Added, path, nil, s.Config.Endpoint, Minor)
+			continue
+		}
+		c.field("skills", path+".type", o.Type, s.Type, Minor)
+		c.field("skills", path+".config.endpoint", o.Config.Endpoint, s.Config.Endpoint, Minor)
+		c.field("skills", path+".config.method", o.Config.Method, s.Config.Method, Minor)
+		c.field("skills", path+".config.headers", o.Config.Headers, s.Config.Headers, Patch)
+		c.field("skills", path+".config.timeout", o.Config.Timeout, s.Config.Timeout, Patch)
+		c.field("skills", path+".config.max_retries", o.Config.MaxRetries, s.Config.MaxRetries, Patch)
+		c.field("skills", path+".config.cost_per_call", o.Config.CostPerCall, s.Config.CostPerCall, Patch)
+		c.field("skills", path+".contract.request", o.Contract.Request, s.Contract.Request, Minor)
+		c.field("skills", path+".contract.response", o.Contract.Response, s.Contract.Response, Minor)
+	}
+}
+
+// nodes 新增或删除节点属于向后兼容的行为变化，提示词修改给出词级差异
+func (c *comparer) nodes(old, new protocol.Topology) {
+	c.field("nodes", "topology.start_at", old.StartAt, new.StartAt, Minor)
+
+	oldMap := make(map[string]protocol.Node)
+	for _, n := range old.Nodes {
+		oldMap[n.ID] = n
+	}
+	newIDs := make(map[string]bool)
+	for _, n := range new.Nodes {
+		newIDs[n.ID] = true
+	}
+
+	for _, n := range old.Nodes {
+		if !newIDs[n.ID] {
+			c.add("nodes", Removed, "nodes."+n.ID, n.Type, nil, Minor)
+		}
+	}
+	for _, n := range new.Nodes {
+		path := "nodes." + n.ID
+		o, ok := oldMap[n.ID]
+		if !ok {
+			c.add("nodes", Added, path, nil, n.Type, Minor)
+			continue
+		}
+		c.field("nodes", path+".type", o.Type, n.Type, Minor)
+
+		for _, key := range unionKeys(o.Config, n.Config) {
+			ov, inOld := o.Config[key]
+			nv, inNew := n.Config[key]
+			keyPath := path + ".config." + key
+			impact := Patch
+			if refKeys[key] {
+				impact = Minor
+			}
+			switch {
+			case !inOld:
+				c.add("nodes", Added, keyPath, nil, nv, impact)
+			case !inNew:
+				c.add("nodes", Removed, keyPath, ov, nil, impact)
+			case !reflect.DeepEqual(ov, nv):
+				ch := c.add("nodes", Changed, keyPath, ov, nv, impact)
+				oldText, oStr := ov.(string)
+				newText, nStr := nv.(string)
+				if promptKeys[key] && oStr && nStr {
+					ch.Words = WordDiff(oldText, newText)
+					ch.Inline = Inline(ch.Words)
+				}
+			}
+		}
+	}
+}
+
+// edgeKey 跳转边的稳定标识：起点 + 类型 (+ 规则序号)
+func edgeKey(e *graph.Edge) string {
+	if e.Kind == executor.EdgeRule {
+		return fmt.Sprintf("%s.rule#%d", e.From, e.Rule+1)
+	}
+	return e.From + "." + e.Kind
+}
+
+// edges 比较跳转关系；新增或删除节点自带的出口边已由节点变更体现，不重复列出
+func (c *comparer) edges(old, new *protocol.RunlyProtocol) {
+	oldNodes := make(map[string]bool)
+	for _, n := range old.Topology.Nodes {
+		oldNodes[n.ID] = true
+	}
+	newNodes := make(map[string]bool)
+	for _, n := range new.Topology.Nodes {
+		newNodes[n.ID] = true
+	}
+
+	oldGraph, newGraph := graph.Build(old), graph.Build(new)
+	oldEdges := make(map[string]*graph.Edge)
+	for _, e := range oldGraph.Edges {
+		oldEdges[edgeKey(e)] = e
+	}
+	newEdges := make(map[string]*graph.Edge)
+	for _, e := range newGraph.Edges {
+		newEdges[edgeKey(e)] = e
+	}
+
+	for _, e := range oldGraph.Edges {
+		if _, ok := newEdges[edgeKey(e)]; !ok && newNodes[e.From] {
+			c.add("edges", Removed, "edges."+edgeKey(e), edgeText(e), nil, Minor)
+		}
+	}
+	for _, e := range newGraph.Edges {
+		o, ok := oldEdges[edgeKey(e)]
+		switch {
+		case !ok:
+			if oldNodes[e.From] {
+				c.add("edges", Added, "edges."+edgeKey(e), nil, edgeText(e), Minor)
+			}
+		case o.To != e.To || o.Label != e.Label:
+			c.add("edges", Changed, "edges."+edgeKey(e), edgeText(o), edgeText(e), Minor)
+		}
+	}
+}
+
+// edgeText 边的目标节点，规则边附带条件
+func edgeText(e *graph.Edge) string {
+	if e.Kind == executor.EdgeRule {
+		_, cond, _ := strings.Cut(e.Label, " ")
+		return e.To + " (if " + cond + ")"
+	}
+	return e.To
+}
+
+// wordRegex 将文本切分为单词与空白，保证拼接后与原文一致
+var wordRegex = regexp.MustCompile(`\s+|[^\s]+`)
+
+// WordDiff 计算两段文本的词级差异，连续的变更合并为一段删除与一段新增
+func WordDiff(old, new string) []textdiff.Op {
+	var ops []textdiff.Op
+	var del, ins strings.Builder
+	flush := func() {
+		if del.Len() > 0 {
+			ops = append(ops, textdiff.Op{Kind: textdiff.Delete, Text: del.String()})
+		}
+		if ins.Len() > 0 {
+			ops = append(ops, textdiff.Op{Kind: textdiff.Insert, Text: ins.String()})
+		}
+		del.Reset()
+		ins.Reset()
+	}
+	for _, op := range textdiff.Diff(wordRegex.FindAllString(old, -1), wordRegex.FindAllString(new, -1)) {
+		switch op.Kind {
+		case textdiff.Delete:
+			del.WriteString(op.Text)
+		case textdiff.Insert:
+			ins.WriteString(op.Text)
+		default:
+			flush()
+			ops = append(ops, op)
+		}
+	}
+	flush()
+	return ops
+}
+
+// Inline 将词级差异渲染为 [-删除-]{+新增+} 标记文本
+func Inline(ops []textdiff.Op) string {
+	var b strings.Builder
+	for _, op := range ops {
+		switch op.Kind {
+		case textdiff.Equal:
+			b.WriteString(op.Text)
+		case textdiff.Delete:
+			b.WriteString("[-" + op.Text + "-]")
+		case textdiff.Insert:
+			b.WriteString("{+" + op.Text + "+}")
+		}
+	}
+	return b.String()
+}
+
+// render 将字段值渲染为单行文本；复合值使用紧凑 JSON
+func render(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool, int, float64:
+		return fmt.Sprint(val)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range []map[string]interface{}{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// subset 判断 a 中的每个值都出现在 b 中
+func subset(a, b []string) bool {
+	set := make(map[string]bool, len(b))
+	for _, v := range b {
+		set[v] = true
+	}
+	for _, v := range a {
+		if !set[v] {
+			return false
+		}
+	}
+	return true
+}
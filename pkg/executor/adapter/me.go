@@ -0,0 +1,138 @@
+package adapter
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeyRecord 身份服务器登记的一把公钥
+type KeyRecord struct {
+	PublicKey string
+	RetiredAt *time.Time // 轮换时间，仍为当前密钥时为 nil
+	RevokedAt *time.Time // 吊销时间，未吊销时为 nil
+}
+
+// LookupKeys 向身份服务器查询 MeID 登记过的公钥 (十六进制)，包括轮换下来的公钥与已吊销的公钥及其时间
+func (c *RequestClient) LookupKeys(meID string) ([]KeyRecord, error) {
+	data, err := c.SetToMeServer().Post("/v1/me/keys/lookup", map[string]interface{}{"me_id": meID})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []KeyRecord
+	if list, ok := data["public_keys"].([]interface{}); ok {
+		for _, k := range list {
+			if s, ok := k.(string); ok && s != "" {
+				keys = append(keys, KeyRecord{PublicKey: s})
+			}
+		}
+	}
+	if k, ok := data["public_key"].(string); ok && k != "" {
+		keys = append(keys, KeyRecord{PublicKey: k})
+	}
+	if list, ok := data["retired_keys"].([]interface{}); ok {
+		for _, item := range list {
+			m, _ := item.(map[string]interface{})
+			k, _ := m["public_key"].(string)
+			at := timeField(m, "retired_at")
+			if k == "" || at == nil {
+				continue
+			}
+			keys = append(keys, KeyRecord{PublicKey: k, RetiredAt: at})
+		}
+	}
+	if list, ok := data["revoked_keys"].([]interface{}); ok {
+		for _, item := range list {
+			m, _ := item.(map[string]interface{})
+			k, _ := m["public_key"].(string)
+			at := timeField(m, "revoked_at")
+			if k == "" || at == nil {
+				continue
+			}
+			keys = append(keys, KeyRecord{PublicKey: k, RetiredAt: timeField(m, "retired_at"), RevokedAt: at})
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no public key registered for %s", meID)
+	}
+	return keys, nil
+}
+
+// timeField 读取 RFC 3339 时间字段，缺失或无法解析时返回 nil
+func timeField(m map[string]interface{}, key string) *time.Time {
+	s, _ := m[key].(string)
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil
+	}
+	return &at
+}
+
+// KeyChallenge 身份服务器为公钥登记签发的一次性挑战
+type KeyChallenge struct {
+	ID        string
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// ProofMessage 持有证明的签名对象：带域分隔前缀，避免签名被挪用到资产或其他协议消息
+func (ch *KeyChallenge) ProofMessage(publicKey string) []byte {
+	return []byte(fmt.Sprintf("runly-me-key-proof/v1\n%s\n%s\n%s", ch.ID, ch.Nonce, publicKey))
+}
+
+// RequestKeyChallenge 为待登记的公钥申请挑战
+func (c *RequestClient) RequestKeyChallenge(publicKey string) (*KeyChallenge, error) {
+	data, err := c.SetToMeServer().Post("/v1/me/keys/challenge", map[string]interface{}{"public_key": publicKey})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := &KeyChallenge{}
+	ch.ID, _ = data["challenge_id"].(string)
+	ch.Nonce, _ = data["nonce"].(string)
+	if s, ok := data["expires_at"].(string); ok {
+		ch.ExpiresAt, _ = time.Parse(time.RFC3339, s)
+	}
+	if ch.ID == "" || ch.Nonce == "" {
+		return nil, fmt.Errorf("invalid challenge response from %s", c.BaseURL)
+	}
+	return ch, nil
+}
+
+// RegisterKey 登记公钥：以挑战签名作为私钥持有证明，只有公钥离开本机，返回服务器分配的 MeID
+func (c *RequestClient) RegisterKey(username, publicKey string, ch *KeyChallenge, proof string) (string, error) {
+	data, err := c.SetToMeServer().Post("/v1/me/keys/register", map[string]interface{}{
+		"username":     username,
+		"public_key":   publicKey,
+		"challenge_id": ch.ID,
+		"signature":    proof,
+	})
+	if err != nil {
+		return "", err
+	}
+	meID, _ := data["me_id"].(string)
+	return meID, nil
+}
+
+// RotateKey 登记密钥轮换：statement 为轮换声明 (JCS)，signature 为旧密钥的签名，proof 为新密钥的持有证明
+func (c *RequestClient) RotateKey(statement []byte, signature, proof string) (string, error) {
+	data, err := c.SetToMeServer().Post("/v1/me/keys/rotate", map[string]interface{}{
+		"statement": string(statement),
+		"signature": signature,
+		"proof":     proof,
+	})
+	if err != nil {
+		return "", err
+	}
+	meID, _ := data["me_id"].(string)
+	return meID, nil
+}
+
+// RevokeKey 登记密钥吊销：statement 为吊销声明 (JCS)，signature 为当前密钥的签名
+func (c *RequestClient) RevokeKey(statement []byte, signature string) error {
+	_, err := c.SetToMeServer().Post("/v1/me/keys/revoke", map[string]interface{}{
+		"statement": string(statement),
+		"signature": signature,
+	})
+	return err
+}
@@ -0,0 +1,187 @@
+package adapter
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/config"
+	"github.com/originbeat-inc/runly-cli/internal/mestub"
+	"github.com/originbeat-inc/runly-cli/pkg/crypto"
+	"github.com/originbeat-inc/runly-cli/pkg/keystore"
+)
+
+// newMeClient 指向本地 Me 身份服务替身的客户端
+func newMeClient(t *testing.T) (*RequestClient, *mestub.Server) {
+	t.Helper()
+	stub := mestub.New()
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	return &RequestClient{Timeout: 5 * time.Second, activeProfile: config.Profile{MeServer: srv.URL}}, stub
+}
+
+func newKey(t *testing.T) (pubHex, seedHex string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(pub), hex.EncodeToString(priv.Seed())
+}
+
+func prove(t *testing.T, ch *KeyChallenge, pubHex, seedHex string) string {
+	t.Helper()
+	proof, err := crypto.Sign(seedHex, ch.ProofMessage(pubHex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+// 挑战-应答登记：只有公钥与持有证明离开本机，登记后可查询到该公钥
+func TestRegisterKeyProofOfPossession(t *testing.T) {
+	client, stub := newMeClient(t)
+	pub, seed := newKey(t)
+
+	ch, err := client.RequestKeyChallenge(pub)
+	if err != nil {
+		t.Fatalf("challenge: %v", err)
+	}
+	if ch.ExpiresAt.IsZero() {
+		t.Error("challenge should carry an expiry")
+	}
+	meID, err := client.RegisterKey("alice", pub, ch, prove(t, ch, pub, seed))
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if meID != "me_alice" {
+		t.Errorf("me_id = %q, want me_alice", meID)
+	}
+
+	keys, err := client.LookupKeys(meID)
+	if err != nil || len(keys) != 1 || keys[0].PublicKey != pub || keys[0].RevokedAt != nil {
+		t.Errorf("lookup: keys=%+v err=%v", keys, err)
+	}
+
+	for path, payloads := range stub.Requests {
+		for _, p := range payloads {
+			for field, v := range p {
+				if s, ok := v.(string); ok && s == seed {
+					t.Errorf("%s: private seed sent in field %q", path, field)
+				}
+			}
+		}
+	}
+}
+
+// 持有证明必须由待登记公钥对应的私钥签名，且挑战只能使用一次
+func TestRegisterKeyRejectsInvalidProof(t *testing.T) {
+	client, stub := newMeClient(t)
+	pub, seed := newKey(t)
+	_, otherSeed := newKey(t)
+
+	ch, err := client.RequestKeyChallenge(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RegisterKey("mallory", pub, ch, prove(t, ch, pub, otherSeed)); err == nil {
+		t.Error("proof signed by another key should be rejected")
+	}
+	if _, err := client.RegisterKey("mallory", pub, ch, prove(t, ch, pub, seed)); err == nil {
+		t.Error("a challenge must not be reusable")
+	}
+
+	// 签名对象绑定挑战 ID、nonce 与公钥，换用其他挑战的签名同样无效
+	ch1, _ := client.RequestKeyChallenge(pub)
+	ch2, _ := client.RequestKeyChallenge(pub)
+	if _, err := client.RegisterKey("mallory", pub, ch2, prove(t, ch1, pub, seed)); err == nil {
+		t.Error("proof for another challenge should be rejected")
+	}
+	if len(stub.Keys("me_mallory")) != 0 {
+		t.Error("no key should have been registered")
+	}
+}
+
+// ProofMessage 带域分隔前缀，不同挑战或公钥得到不同的签名对象
+func TestProofMessage(t *testing.T) {
+	ch := &KeyChallenge{ID: "c1", Nonce: "n1"}
+	want := "runly-me-key-proof/v1\nc1\nn1\nabcd"
+	if got := string(ch.ProofMessage("abcd")); got != want {
+		t.Errorf("ProofMessage = %q, want %q", got, want)
+	}
+	other := &KeyChallenge{ID: "c2", Nonce: "n1"}
+	if string(other.ProofMessage("abcd")) == want {
+		t.Error("challenge ID must be part of the proof message")
+	}
+}
+
+// 已登记的 MeID 不能被他人以同名重新登记接管，只能通过当前密钥签名的轮换追加密钥
+func TestRegisterKeyRejectsTakeover(t *testing.T) {
+	client, stub := newMeClient(t)
+	pub, seed := newKey(t)
+	ch, _ := client.RequestKeyChallenge(pub)
+	if _, err := client.RegisterKey("carol", pub, ch, prove(t, ch, pub, seed)); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	attacker, attackerSeed := newKey(t)
+	ch, _ = client.RequestKeyChallenge(attacker)
+	if _, err := client.RegisterKey("carol", attacker, ch, prove(t, ch, attacker, attackerSeed)); err == nil {
+		t.Error("a second registration of the same username should fail")
+	}
+	if keys := stub.Keys("me_carol"); len(keys) != 1 || keys[0] != pub {
+		t.Errorf("me_carol keys = %v, want only the original key", keys)
+	}
+
+	// 同一公钥重复登记是幂等的
+	ch, _ = client.RequestKeyChallenge(pub)
+	if meID, err := client.RegisterKey("carol", pub, ch, prove(t, ch, pub, seed)); err != nil || meID != "me_carol" {
+		t.Errorf("re-register: me_id=%q err=%v", meID, err)
+	}
+}
+
+// 轮换登记后，查询结果以服务端时间标明旧公钥的轮换时间，其他设备据此拒绝轮换后的旧密钥签名
+func TestLookupKeysReportsRotation(t *testing.T) {
+	client, _ := newMeClient(t)
+	oldPub, oldSeed := newKey(t)
+	ch, _ := client.RequestKeyChallenge(oldPub)
+	meID, err := client.RegisterKey("dave", oldPub, ch, prove(t, ch, oldPub, oldSeed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newPub, newSeed := newKey(t)
+	// 声明中的时间由签名者控制，服务端不采信
+	statement, err := keystore.RotationStatement(meID, oldPub, newPub, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, _ := crypto.Sign(oldSeed, statement)
+	proof, _ := crypto.Sign(newSeed, statement)
+	before := time.Now().Add(-time.Second)
+	if _, err := client.RotateKey(statement, signature, proof); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	keys, err := client.LookupKeys(meID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]KeyRecord)
+	for _, k := range keys {
+		got[k.PublicKey] = k
+	}
+	if k := got[newPub]; k.RetiredAt != nil || k.RevokedAt != nil {
+		t.Errorf("current key: %+v", k)
+	}
+	old, ok := got[oldPub]
+	if !ok || old.RetiredAt == nil {
+		t.Fatalf("old key should be reported as retired: %+v", keys)
+	}
+	if old.RetiredAt.Before(before) || old.RetiredAt.After(time.Now()) {
+		t.Errorf("retired_at = %v, want the server's rotation time", old.RetiredAt)
+	}
+}
@@ -0,0 +1,77 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+)
+
+// DefaultSkillTimeout 技能未声明超时时间时的默认值
+const DefaultSkillTimeout = 30 * time.Second
+
+// CallEndpoint 向技能/知识库服务发送 JSON 请求，并按 maxRetries 进行重试
+// 返回值为解析后的 JSON 响应体；非 JSON 响应按原始字符串返回
+func CallEndpoint(method, endpoint string, headers map[string]string, body interface{}, timeout time.Duration, maxRetries int) (interface{}, error) {
+	if timeout <= 0 {
+		timeout = DefaultSkillTimeout
+	}
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		result, err := doCall(method, endpoint, headers, payload, timeout)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func doCall(method, endpoint string, headers map[string]string, payload []byte, timeout time.Duration) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.network_err"), err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.network_err"), err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.network_err"), err)
+	}
+	if resp.StatusCode >= 400 {
+		// 🌐 服务端返回 HTTP %d: %s
+		return nil, fmt.Errorf(i18n.T("errors.http_status"), resp.StatusCode, string(data))
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return string(data), nil
+	}
+	return result, nil
+}
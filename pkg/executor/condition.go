@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"strconv"
+	"strings"
+)
+
+// conditionOperators 按匹配优先级排列，长运算符必须排在前面
+var conditionOperators = []string{"==", "!=", ">=", "<=", ">", "<", " contains "}
+
+// EvalCondition 计算 LOGIC_GATE 规则的条件表达式
+// 支持 default / else / true / false、比较运算 (== != > < >= <=) 与 contains，
+// 两侧操作数会先执行变量插值，数字按数值比较，其余按字符串比较
+func EvalCondition(cond string, ctx *Context) bool {
+	cond = strings.TrimSpace(cond)
+	switch strings.ToLower(cond) {
+	case "", "default", "else", "true", "always":
+		return true
+	case "false":
+		return false
+	}
+
+	for _, op := range conditionOperators {
+		idx := indexOutsideBraces(cond, op)
+		if idx < 0 {
+			continue
+		}
+		left := operand(cond[:idx], ctx)
+		right := operand(cond[idx+len(op):], ctx)
+		return compare(left, strings.TrimSpace(op), right)
+	}
+
+	// 无运算符时按真值判断
+	return truthy(operand(cond, ctx))
+}
+
+// operand 渲染并清理操作数（去除空白与包裹引号）
+func operand(raw string, ctx *Context) string {
+	v := strings.TrimSpace(RenderTemplate(strings.TrimSpace(raw), ctx))
+	if len(v) >= 2 {
+		if (v[0] == '"' && v[len(v)-1] == '"') || (v[0] == '\'' && v[len(v)-1] == '\'') {
+			v = v[1 : len(v)-1]
+		}
+	}
+	return v
+}
+
+func compare(left, op, right string) bool {
+	if op == "contains" {
+		return strings.Contains(left, right)
+	}
+
+	lf, lerr := strconv.ParseFloat(left, 64)
+	rf, rerr := strconv.ParseFloat(right, 64)
+	if lerr == nil && rerr == nil {
+		switch op {
+		case "==":
+			return lf == rf
+		case "!=":
+			return lf != rf
+		case ">":
+			return lf > rf
+		case "<":
+			return lf < rf
+		case ">=":
+			return lf >= rf
+		case "<=":
+			return lf <= rf
+		}
+	}
+
+	switch op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	case ">":
+		return left > right
+	case "<":
+		return left < right
+	case ">=":
+		return left >= right
+	case "<=":
+		return left <= right
+	}
+	return false
+}
+
+func truthy(v string) bool {
+	switch strings.ToLower(v) {
+	case "", "0", "false", "no", "null", "<nil>":
+		return false
+	}
+	return !strings.HasPrefix(v, "<!")
+}
+
+// indexOutsideBraces 查找不位于 {{ }} 占位符内部的运算符位置
+func indexOutsideBraces(s, op string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "{{") {
+			depth++
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], "}}") && depth > 0 {
+			depth--
+			i++
+			continue
+		}
+		if depth == 0 && strings.HasPrefix(s[i:], op) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,119 @@
+package executor
+
+import (
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// NodeCost 单个节点的资源消耗
+type NodeCost struct {
+	NodeID           string        `json:"node_id"`
+	Type             string        `json:"type"`
+	PromptTokens     int           `json:"prompt_tokens,omitempty"`
+	CompletionTokens int           `json:"completion_tokens,omitempty"`
+	Cost             float64       `json:"cost"`
+	Duration         time.Duration `json:"duration_ns"`
+}
+
+// CostSummary 一次运行的成本汇总
+type CostSummary struct {
+	Currency         string        `json:"currency"`
+	Budget           float64       `json:"budget,omitempty"`
+	PromptTokens     int           `json:"prompt_tokens"`
+	CompletionTokens int           `json:"completion_tokens"`
+	Total            float64       `json:"total"`
+	WallTime         time.Duration `json:"wall_time_ns"`
+	Nodes            []NodeCost    `json:"nodes"`
+}
+
+// Meter 运行计量器：累计 Token 与技能调用开销，并在超出预算前中止运行
+type Meter struct {
+	TokenPrice float64 // 每 1K Token 的默认单价，可被节点 config.token_price 覆盖
+	Budget     float64 // 单次运行的支出上限，0 表示不限制
+	Summary    *CostSummary
+}
+
+// NewMeter 创建计量器，币种沿用 Commerce.Pricing 的声明
+func NewMeter(p *protocol.RunlyProtocol, tokenPrice, budget float64) *Meter {
+	currency := p.Commerce.Pricing.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	return &Meter{
+		TokenPrice: tokenPrice,
+		Budget:     budget,
+		Summary:    &CostSummary{Currency: currency, Budget: budget, Nodes: []NodeCost{}},
+	}
+}
+
+// EstimateTokens 以约 4 字符 / Token 的经验比例估算文本的 Token 数
+func EstimateTokens(text string) int {
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) / 4))
+}
+
+// Project 预估节点执行开销，若累计支出将超出预算则返回错误
+func (m *Meter) Project(estimate float64) error {
+	if m.Budget <= 0 {
+		return nil
+	}
+	if projected := m.Summary.Total + estimate; projected > m.Budget {
+		// 💸 预计支出 %.4f %s 将超出预算 %.4f
+		return fmt.Errorf(i18n.T("errors.budget_exceeded"), projected, m.Summary.Currency, m.Budget)
+	}
+	return nil
+}
+
+// Record 记录节点实际产生的开销
+func (m *Meter) Record(c NodeCost) {
+	m.Summary.Nodes = append(m.Summary.Nodes, c)
+	m.Summary.PromptTokens += c.PromptTokens
+	m.Summary.CompletionTokens += c.CompletionTokens
+	m.Summary.Total += c.Cost
+}
+
+// TokenCost 按节点单价计算 Token 费用
+func (m *Meter) TokenCost(n *protocol.Node, tokens int) float64 {
+	price := m.TokenPrice
+	if v, ok := toFloat(n.Config["token_price"]); ok {
+		price = v
+	}
+	return float64(tokens) / 1000 * price
+}
+
+// EstimateNode 在执行前估算节点开销：技能按次计费，AI 任务按 prompt 与 max_tokens 估算
+func (m *Meter) EstimateNode(p *protocol.RunlyProtocol, n *protocol.Node, ctx *Context) float64 {
+	switch n.Type {
+	case "SKILL_CALL":
+		ref, _ := n.Config["skill_ref"].(string)
+		for _, s := range p.Skills {
+			if s.ID == ref {
+				return s.Config.CostPerCall
+			}
+		}
+	case "AI_TASK":
+		prompt, _ := n.Config["prompt"].(string)
+		tokens := EstimateTokens(RenderTemplate(prompt, ctx))
+		if maxTokens, ok := toFloat(n.Config["max_tokens"]); ok {
+			tokens += int(maxTokens)
+		}
+		return m.TokenCost(n, tokens)
+	}
+	return 0
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/pkg/cache"
+	"github.com/originbeat-inc/runly-cli/pkg/executor/adapter"
+)
+
+// callOptions 单次外部调用的超时、重试与缓存策略
+type callOptions struct {
+	Timeout    int // 秒
+	MaxRetries int
+	CacheTTL   int // 秒，0 表示不缓存
+}
+
+// call 发送请求；启用缓存时优先命中磁盘缓存，返回值 cached 标识是否来自缓存
+func (e *Engine) call(req *HTTPRequest, opts callOptions) (result interface{}, cached bool, err error) {
+	ttl := opts.CacheTTL
+	if ttl <= 0 {
+		ttl = e.CacheTTL
+	}
+	useCache := e.Cache != nil && ttl > 0
+
+	var key, bodyHash string
+	if useCache {
+		body, _ := json.Marshal(req.Body)
+		key, bodyHash = cache.Key(req.Method, req.Endpoint, body)
+		if entry, ok := e.Cache.Get(key); ok {
+			if err := json.Unmarshal(entry.Response, &result); err == nil {
+				return result, true, nil
+			}
+		}
+	}
+
+	// 缓存键基于未解析的请求计算，密钥只出现在真正发出的请求中
+	resolved, err := req.Resolve()
+	if err != nil {
+		return nil, false, err
+	}
+
+	result, err = adapter.CallEndpoint(resolved.Method, resolved.Endpoint, resolved.Headers, resolved.Body,
+		time.Duration(opts.Timeout)*time.Second, opts.MaxRetries)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if useCache {
+		if raw, mErr := json.Marshal(result); mErr == nil {
+			now := time.Now()
+			_ = e.Cache.Put(&cache.Entry{
+				Key:       key,
+				Kind:      req.Kind,
+				Ref:       req.Ref,
+				Method:    req.Method,
+				Endpoint:  req.Endpoint,
+				BodyHash:  bodyHash,
+				CreatedAt: now,
+				ExpiresAt: now.Add(time.Duration(ttl) * time.Second),
+				Response:  raw,
+			})
+		}
+	}
+	return result, false, nil
+}
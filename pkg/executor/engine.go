@@ -2,9 +2,11 @@ package executor
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"github.com/originbeat-inc/runly-cli/internal/ui"
+	"github.com/originbeat-inc/runly-cli/pkg/cache"
 	"github.com/originbeat-inc/runly-cli/pkg/protocol"
 )
 
@@ -18,6 +20,15 @@ type Context struct {
 type Engine struct {
 	Protocol *protocol.RunlyProtocol
 	Context  *Context
+	Trace    *Trace // 本次运行的执行轨迹
+	Meter    *Meter // 成本与 Token 计量
+
+	Live     bool         // 真实调用模式：向技能与知识库发送请求，默认仿真
+	Cache    *cache.Store // 响应缓存，nil 表示未启用
+	CacheTTL int          // 资源未声明 cache_ttl 时使用的默认有效期 (秒)
+
+	current NodeCost // 当前节点的计量数据
+	cached  bool     // 当前节点是否命中缓存
 }
 
 // NewEngine 初始化引擎并注入初始输入
@@ -31,6 +42,11 @@ func NewEngine(p *protocol.RunlyProtocol, inputs map[string]interface{}) *Engine
 			},
 			Artifacts: make(map[string]interface{}),
 		},
+		Trace: &Trace{
+			URN:     p.Manifest.URN,
+			Version: p.Manifest.Version,
+		},
+		Meter: NewMeter(p, 0, 0),
 	}
 }
 
@@ -38,6 +54,13 @@ func NewEngine(p *protocol.RunlyProtocol, inputs map[string]interface{}) *Engine
 func (e *Engine) Run() error {
 	ui.PrintHeader("executor.engine_header")
 
+	e.Trace.StartedAt = time.Now()
+	e.Trace.Cost = e.Meter.Summary
+	defer func() {
+		e.Trace.FinishedAt = time.Now()
+		e.Meter.Summary.WallTime = e.Trace.FinishedAt.Sub(e.Trace.StartedAt)
+	}()
+
 	currentNodeID := e.Protocol.Topology.StartAt
 	for {
 		if currentNodeID == "terminate" || currentNodeID == "" {
@@ -53,23 +76,59 @@ func (e *Engine) Run() error {
 		// 输出当前步骤：正在执行节点 [%s] (%s)
 		ui.PrintStep("executor.step_executing", node.ID, node.Type)
 
-		nextID, err := e.executeNode(node)
+		// 预算守卫：执行前估算开销，超出预算则中止运行
+		e.current = NodeCost{NodeID: node.ID, Type: node.Type}
+		e.cached = false
+		if err := e.Meter.Project(e.Meter.EstimateNode(e.Protocol, node, e.Context)); err != nil {
+			e.record(node, Transition{Rule: -1}, err)
+			e.Trace.Steps[len(e.Trace.Steps)-1].Aborted = true
+			return err
+		}
+
+		started := time.Now()
+		next, err := e.executeNode(node)
+		e.current.Duration = time.Since(started)
+		e.Meter.Record(e.current)
+
 		if err != nil {
 			if node.OnFailure != "" {
+				e.record(node, Transition{Next: node.OnFailure, Edge: EdgeFailure, Rule: -1}, err)
 				// 打印跳转提示：条件不匹配或执行失败，正在跳转至错误处理分支
 				ui.PrintStep("executor.node_jump", node.OnFailure)
 				currentNodeID = node.OnFailure
 				continue
 			}
+			e.record(node, Transition{Rule: -1}, err)
 			return err
 		}
-		currentNodeID = nextID
+		e.record(node, next, nil)
+		currentNodeID = next.Next
 	}
 
 	ui.PrintSuccess("executor.execution_complete")
 	return nil
 }
 
+// record 向执行轨迹追加一条节点记录
+func (e *Engine) record(n *protocol.Node, t Transition, err error) {
+	step := TraceStep{
+		NodeID: n.ID,
+		Type:   n.Type,
+		Next:   t.Next,
+		Edge:   t.Edge,
+		Rule:   t.Rule,
+
+		Tokens:   e.current.PromptTokens + e.current.CompletionTokens,
+		Cost:     e.current.Cost,
+		Duration: e.current.Duration,
+		Cached:   e.cached,
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	e.Trace.Steps = append(e.Trace.Steps, step)
+}
+
 func (e *Engine) findNode(id string) *protocol.Node {
 	for _, n := range e.Protocol.Topology.Nodes {
 		if n.ID == id {
@@ -79,8 +138,93 @@ func (e *Engine) findNode(id string) *protocol.Node {
 	return nil
 }
 
-func (e *Engine) executeNode(n *protocol.Node) (string, error) {
+func (e *Engine) findSkill(id string) *protocol.SkillResource {
+	for _, s := range e.Protocol.Skills {
+		if s.ID == id {
+			return &s
+		}
+	}
+	return nil
+}
+
+func (e *Engine) findKnowledge(id string) *protocol.KnowledgeResource {
+	for _, k := range e.Protocol.Knowledge {
+		if k.ID == id {
+			return &k
+		}
+	}
+	return nil
+}
+
+// callTracked 发送请求并记录缓存命中情况
+func (e *Engine) callTracked(req *HTTPRequest, opts callOptions) (interface{}, error) {
+	result, cached, err := e.call(req, opts)
+	if cached {
+		e.cached = true
+		// 输出：💾 命中缓存: %s
+		ui.PrintStep("executor.cache_hit", req.Ref)
+	}
+	return result, err
+}
+
+// injectKnowledge 检索知识库并按 injection 配置写入上下文
+func (e *Engine) injectKnowledge(n *protocol.Node, ref string) error {
+	kb := e.findKnowledge(ref)
+	if kb == nil {
+		return fmt.Errorf(i18n.T("errors.kb_ref_missing"), n.ID, ref)
+	}
+
+	var result interface{} = "MOCK_KNOWLEDGE_DATA"
+	if e.Live && kb.Config.Endpoint != "" {
+		var err error
+		result, err = e.callTracked(BuildKnowledgeRequest(*kb, KnowledgeQuery(n, e.Context), e.Context), callOptions{
+			Timeout:    kb.Config.Timeout,
+			MaxRetries: kb.Config.MaxRetries,
+			CacheTTL:   kb.Injection.CacheTTL,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	target := kb.Injection.TargetVariable
+	if target == "" {
+		target = kb.ID
+	}
+	knowledge, _ := e.Context.Vars["knowledge"].(map[string]interface{})
+	if knowledge == nil {
+		knowledge = make(map[string]interface{})
+		e.Context.Vars["knowledge"] = knowledge
+	}
+	knowledge[target] = truncateTokens(result, kb.Injection.MaxTokens)
+	return nil
+}
+
+// KnowledgeQuery 返回知识检索语句：优先使用 config.query，否则使用渲染后的 prompt
+func KnowledgeQuery(n *protocol.Node, ctx *Context) string {
+	if q, _ := n.Config["query"].(string); q != "" {
+		return RenderTemplate(q, ctx)
+	}
+	prompt, _ := n.Config["prompt"].(string)
+	return RenderTemplate(prompt, ctx)
+}
+
+// truncateTokens 按 max_tokens 截断文本型检索结果
+func truncateTokens(v interface{}, maxTokens int) interface{} {
+	text, ok := v.(string)
+	if !ok || maxTokens <= 0 {
+		return v
+	}
+	runes := []rune(text)
+	if limit := maxTokens * 4; len(runes) > limit {
+		return string(runes[:limit])
+	}
+	return text
+}
+
+func (e *Engine) executeNode(n *protocol.Node) (Transition, error) {
 	steps := e.Context.Vars["steps"].(map[string]interface{})
+	success := Transition{Next: n.OnSuccess, Edge: EdgeSuccess, Rule: -1}
 
 	switch n.Type {
 	case "SKILL_CALL":
@@ -88,18 +232,55 @@ func (e *Engine) executeNode(n *protocol.Node) (string, error) {
 		// 输出：📡 正在连接服务端: %s
 		ui.PrintStep("executor.skill_calling", skillRef)
 
-		steps[n.ID] = map[string]interface{}{"output": "MOCK_SKILL_DATA"}
-		return n.OnSuccess, nil
+		skill := e.findSkill(skillRef)
+		if skill == nil {
+			return Transition{Rule: -1}, fmt.Errorf(i18n.T("errors.skill_ref_missing"), n.ID, skillRef)
+		}
+
+		// 仿真模式 (默认) 或未声明服务地址时返回模拟数据
+		if !e.Live || skill.Config.Endpoint == "" {
+			e.current.Cost = skill.Config.CostPerCall
+			steps[n.ID] = map[string]interface{}{"output": "MOCK_SKILL_DATA"}
+			return success, nil
+		}
+
+		output, err := e.callTracked(BuildSkillRequest(*skill, e.Context), callOptions{
+			Timeout:    skill.Config.Timeout,
+			MaxRetries: skill.Config.MaxRetries,
+			CacheTTL:   skill.Config.CacheTTL,
+		})
+		if err != nil {
+			return Transition{Rule: -1}, err
+		}
+		// 命中缓存的调用不产生费用
+		if !e.cached {
+			e.current.Cost = skill.Config.CostPerCall
+		}
+		steps[n.ID] = map[string]interface{}{"output": output}
+		return success, nil
 
 	case "AI_TASK":
 		// 输出：🤖 正在执行 AI 推理任务...
 		ui.PrintStep("executor.ai_processing")
 
+		// 知识注入：检索结果写入 knowledge.<target_variable>，供 prompt 引用
+		if ref, _ := n.Config["knowledge_ref"].(string); ref != "" {
+			if err := e.injectKnowledge(n, ref); err != nil {
+				return Transition{Rule: -1}, err
+			}
+		}
+
 		prompt, _ := n.Config["prompt"].(string)
 		rendered := RenderTemplate(prompt, e.Context)
+		output := "AI_RESULT_FOR_" + rendered
 
-		steps[n.ID] = map[string]interface{}{"output": "AI_RESULT_FOR_" + rendered}
-		return n.OnSuccess, nil
+		// Token 计量：仿真模式下按文本长度估算
+		e.current.PromptTokens = EstimateTokens(rendered)
+		e.current.CompletionTokens = EstimateTokens(output)
+		e.current.Cost = e.Meter.TokenCost(n, e.current.PromptTokens+e.current.CompletionTokens)
+
+		steps[n.ID] = map[string]interface{}{"output": output}
+		return success, nil
 
 	case "HITL":
 		instruction, _ := n.Config["instruction"].(string)
@@ -108,7 +289,22 @@ func (e *Engine) executeNode(n *protocol.Node) (string, error) {
 		// 输出：⌨️  按回车键 [Enter] 模拟专家授权...
 		ui.PrintStep("executor.hitl_continue")
 		fmt.Scanln()
-		return n.OnSuccess, nil
+		return success, nil
+
+	case "LOGIC_GATE":
+		// 按声明顺序匹配规则，首个成立的条件决定下游节点
+		for i, rule := range n.Rules {
+			if EvalCondition(rule.Condition, e.Context) {
+				// 输出：🔀 规则 #%d 命中: %s
+				ui.PrintStep("executor.rule_matched", i+1, rule.Condition)
+				return Transition{Next: rule.Next, Edge: EdgeRule, Rule: i}, nil
+			}
+		}
+		if n.OnSuccess != "" {
+			return success, nil
+		}
+		// 🔀 逻辑网关 [%s] 没有任何规则命中
+		return Transition{Rule: -1}, fmt.Errorf(i18n.T("errors.no_rule_matched"), n.ID)
 
 	case "TERMINUS":
 		artifactRef, _ := n.Config["artifact_ref"].(string)
@@ -117,9 +313,9 @@ func (e *Engine) executeNode(n *protocol.Node) (string, error) {
 		// 模拟渲染最终数据
 		finalData := RenderTemplate("{{"+dataSource+"}}", e.Context)
 		e.Context.Artifacts[artifactRef] = finalData
-		return "terminate", nil
+		return Transition{Next: "terminate", Edge: EdgeTerminus, Rule: -1}, nil
 
 	default:
-		return n.OnSuccess, nil
+		return success, nil
 	}
 }
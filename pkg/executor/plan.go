@@ -0,0 +1,143 @@
+package executor
+
+import (
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Plan 是一次 dry-run 的执行计划：遍历全部可达节点，但不发送任何请求
+type Plan struct {
+	URN         string                 `json:"urn"`
+	StartAt     string                 `json:"start_at"`
+	Inputs      map[string]interface{} `json:"inputs"`
+	Steps       []PlanStep             `json:"steps"`
+	Unreachable []string               `json:"unreachable"`
+}
+
+// PlanStep 计划中的单个节点
+type PlanStep struct {
+	NodeID   string         `json:"node_id"`
+	Type     string         `json:"type"`
+	Prompt   string         `json:"prompt,omitempty"`
+	Requests []*HTTPRequest `json:"requests,omitempty"`
+	Branches []PlanBranch   `json:"branches,omitempty"`
+	Artifact string         `json:"artifact,omitempty"`
+}
+
+// PlanBranch 节点的一条可能出口
+type PlanBranch struct {
+	Edge      string `json:"edge"`
+	Condition string `json:"condition,omitempty"`
+	Next      string `json:"next"`
+}
+
+// BuildPlan 从 start_at 出发按广度优先遍历拓扑，渲染模板并列出全部潜在分支
+func BuildPlan(p *protocol.RunlyProtocol, inputs map[string]interface{}) *Plan {
+	ctx := planContext(p, inputs)
+	plan := &Plan{
+		URN:         p.Manifest.URN,
+		StartAt:     p.Topology.StartAt,
+		Inputs:      inputs,
+		Steps:       []PlanStep{},
+		Unreachable: []string{},
+	}
+
+	nodes := make(map[string]protocol.Node)
+	for _, n := range p.Topology.Nodes {
+		nodes[n.ID] = n
+	}
+
+	visited := make(map[string]bool)
+	queue := []string{p.Topology.StartAt}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		n, ok := nodes[id]
+		if !ok || visited[id] {
+			continue
+		}
+		visited[id] = true
+
+		step := planNode(p, n, ctx)
+		for _, b := range step.Branches {
+			queue = append(queue, b.Next)
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	for _, n := range p.Topology.Nodes {
+		if !visited[n.ID] {
+			plan.Unreachable = append(plan.Unreachable, n.ID)
+		}
+	}
+	return plan
+}
+
+// planNode 渲染单个节点将要执行的动作
+func planNode(p *protocol.RunlyProtocol, n protocol.Node, ctx *Context) PlanStep {
+	step := PlanStep{NodeID: n.ID, Type: n.Type}
+
+	switch n.Type {
+	case "SKILL_CALL":
+		ref, _ := n.Config["skill_ref"].(string)
+		for _, s := range p.Skills {
+			if s.ID == ref {
+				step.Requests = append(step.Requests, BuildSkillRequest(s, ctx).Masked())
+			}
+		}
+
+	case "AI_TASK":
+		prompt, _ := n.Config["prompt"].(string)
+		step.Prompt = RenderTemplate(prompt, ctx)
+		if ref, _ := n.Config["knowledge_ref"].(string); ref != "" {
+			for _, kb := range p.Knowledge {
+				if kb.ID == ref {
+					step.Requests = append(step.Requests, BuildKnowledgeRequest(kb, KnowledgeQuery(&n, ctx), ctx).Masked())
+				}
+			}
+		}
+
+	case "HITL":
+		instruction, _ := n.Config["instruction"].(string)
+		step.Prompt = RenderTemplate(instruction, ctx)
+
+	case "LOGIC_GATE":
+		for _, r := range n.Rules {
+			step.Branches = append(step.Branches, PlanBranch{Edge: EdgeRule, Condition: r.Condition, Next: r.Next})
+		}
+
+	case "TERMINUS":
+		step.Artifact, _ = n.Config["artifact_ref"].(string)
+		step.Branches = append(step.Branches, PlanBranch{Edge: EdgeTerminus, Next: "terminate"})
+	}
+
+	if n.OnSuccess != "" {
+		step.Branches = append(step.Branches, PlanBranch{Edge: EdgeSuccess, Next: n.OnSuccess})
+	}
+	if n.OnFailure != "" {
+		step.Branches = append(step.Branches, PlanBranch{Edge: EdgeFailure, Next: n.OnFailure})
+	}
+	return step
+}
+
+// planContext 构造计划阶段的上下文：步骤产出尚不存在，以引用路径本身作为占位值
+func planContext(p *protocol.RunlyProtocol, inputs map[string]interface{}) *Context {
+	steps := make(map[string]interface{})
+	for _, n := range p.Topology.Nodes {
+		steps[n.ID] = map[string]interface{}{"output": "<steps." + n.ID + ".output>"}
+	}
+	knowledge := make(map[string]interface{})
+	for _, kb := range p.Knowledge {
+		if kb.Injection.TargetVariable != "" {
+			knowledge[kb.Injection.TargetVariable] = "<knowledge." + kb.Injection.TargetVariable + ">"
+		}
+	}
+	return &Context{
+		Vars: map[string]interface{}{
+			"inputs":    inputs,
+			"steps":     steps,
+			"knowledge": knowledge,
+		},
+		Artifacts: make(map[string]interface{}),
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 )
 
+// secretDomains 密钥占位符所在的域，渲染时原样保留，仅在发送请求前由 HTTPRequest.Resolve 解析
+var secretDomains = map[string]bool{"env": true, "secret": true}
+
 // RenderTemplate 执行变量插值，例如将 {{inputs.topic}} 替换为实际值
 func RenderTemplate(tpl string, ctx *Context) string {
 	// 匹配 {{...}} 格式的变量
@@ -23,19 +26,32 @@ func RenderTemplate(tpl string, ctx *Context) string {
 			return fmt.Sprintf("<! %s: %s !>", i18n.T("errors.var_format_err"), path)
 		}
 
-		domain := parts[0]
-		key := parts[1]
+		// 密钥占位符不参与模板渲染，避免密钥进入 prompt 或日志
+		if secretDomains[parts[0]] {
+			return match
+		}
 
-		// 从 Context.Vars 中检索数据
-		if data, ok := ctx.Vars[domain]; ok {
-			if domainMap, ok := data.(map[string]interface{}); ok {
-				if val, exists := domainMap[key]; exists {
-					return fmt.Sprintf("%v", val)
-				}
-			}
+		// 从 Context.Vars 中逐级检索数据，支持 steps.node_id.output 这类多级路径
+		if val, ok := lookupPath(ctx.Vars, parts); ok {
+			return fmt.Sprintf("%v", val)
 		}
 
 		// 提示：引用的变量不存在
 		return fmt.Sprintf("<! %s: %s !>", i18n.T("errors.input_ref_missing"), path)
 	})
 }
+
+// lookupPath 沿路径逐级访问嵌套 map
+func lookupPath(vars map[string]interface{}, parts []string) (interface{}, bool) {
+	var cur interface{} = vars
+	for _, p := range parts {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[p]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
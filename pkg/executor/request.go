@@ -0,0 +1,165 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// HTTPRequest 描述执行器将要发往外部服务的一次请求
+type HTTPRequest struct {
+	Kind     string            `json:"kind"` // skill | knowledge
+	Ref      string            `json:"ref"`
+	Method   string            `json:"method"`
+	Endpoint string            `json:"endpoint"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Body     interface{}       `json:"body,omitempty"`
+}
+
+// BuildSkillRequest 根据技能契约渲染出完整的 HTTP 请求
+func BuildSkillRequest(skill protocol.SkillResource, ctx *Context) *HTTPRequest {
+	return &HTTPRequest{
+		Kind:     "skill",
+		Ref:      skill.ID,
+		Method:   methodOrDefault(skill.Config.Method),
+		Endpoint: RenderTemplate(skill.Config.Endpoint, ctx),
+		Headers:  renderHeaders(skill.Config.Headers, ctx),
+		Body:     RenderValue(skill.Contract.Request, ctx),
+	}
+}
+
+// BuildKnowledgeRequest 根据知识库配置与检索语句渲染出 HTTP 请求
+func BuildKnowledgeRequest(kb protocol.KnowledgeResource, query string, ctx *Context) *HTTPRequest {
+	body := map[string]interface{}{
+		"query":      query,
+		"max_tokens": kb.Injection.MaxTokens,
+	}
+	if p := kb.Config.VDBParams; p != nil {
+		body["index_name"] = p.IndexName
+		body["top_k"] = p.TopK
+		body["threshold"] = p.Threshold
+		body["embedding_model"] = p.EmbeddingModel
+	}
+
+	return &HTTPRequest{
+		Kind:     "knowledge",
+		Ref:      kb.ID,
+		Method:   methodOrDefault(kb.Config.Method),
+		Endpoint: RenderTemplate(kb.Config.Endpoint, ctx),
+		Headers:  renderHeaders(kb.Config.Headers, ctx),
+		Body:     body,
+	}
+}
+
+// Masked 返回请求头脱敏后的副本，用于展示与日志
+func (r *HTTPRequest) Masked() *HTTPRequest {
+	masked := *r
+	masked.Headers = make(map[string]string, len(r.Headers))
+	for k, v := range r.Headers {
+		if protocol.IsSensitiveHeader(k) {
+			v = maskSecret(v)
+		}
+		masked.Headers[k] = v
+	}
+	return &masked
+}
+
+// Resolve 返回解析了密钥占位符的请求副本，仅用于实际发送，不应用于展示、缓存或轨迹
+func (r *HTTPRequest) Resolve() (*HTTPRequest, error) {
+	resolved := *r
+
+	var err error
+	if resolved.Endpoint, err = protocol.ResolveSecrets(r.Endpoint); err != nil {
+		return nil, err
+	}
+
+	resolved.Headers = make(map[string]string, len(r.Headers))
+	for k, v := range r.Headers {
+		if resolved.Headers[k], err = protocol.ResolveSecrets(v); err != nil {
+			return nil, err
+		}
+	}
+
+	if resolved.Body, err = resolveValue(r.Body); err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// resolveValue 递归解析嵌套结构中的密钥占位符
+func resolveValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		return protocol.ResolveSecrets(val)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			r, err := resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			r, err := resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// RenderValue 对任意嵌套结构中的字符串执行变量插值
+func RenderValue(v interface{}, ctx *Context) interface{} {
+	switch val := v.(type) {
+	case string:
+		return RenderTemplate(val, ctx)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = RenderValue(item, ctx)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = RenderValue(item, ctx)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func renderHeaders(headers map[string]string, ctx *Context) map[string]string {
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		out[k] = RenderTemplate(v, ctx)
+	}
+	return out
+}
+
+func methodOrDefault(m string) string {
+	if m == "" {
+		return "POST"
+	}
+	return strings.ToUpper(m)
+}
+
+// maskSecret 保留认证方案前缀 (如 Bearer)，其余内容替换为掩码
+func maskSecret(v string) string {
+	if scheme, _, ok := strings.Cut(v, " "); ok {
+		return scheme + " ****"
+	}
+	if v == "" {
+		return v
+	}
+	return "****"
+}
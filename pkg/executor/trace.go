@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+)
+
+// 跳转边的类型标识
+const (
+	EdgeSuccess  = "on_success"
+	EdgeFailure  = "on_failure"
+	EdgeRule     = "rule"
+	EdgeTerminus = "terminus"
+)
+
+// Trace 记录一次运行实际经过的节点、跳转边与逻辑规则，是覆盖率统计与回放的原始数据
+type Trace struct {
+	URN        string       `json:"urn"`
+	Version    string       `json:"version"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at"`
+	Steps      []TraceStep  `json:"steps"`
+	Cost       *CostSummary `json:"cost,omitempty"`
+}
+
+// TraceStep 单个节点的执行记录
+type TraceStep struct {
+	NodeID string `json:"node_id"`
+	Type   string `json:"type"`
+	Next   string `json:"next,omitempty"`
+	Edge   string `json:"edge,omitempty"` // on_success | on_failure | rule | terminus
+	Rule   int    `json:"rule"`           // 命中的 LOGIC_GATE 规则下标，未命中为 -1
+	Error  string `json:"error,omitempty"`
+
+	// Aborted 节点被预算守卫拒绝、从未执行，不计入覆盖率
+	Aborted bool `json:"aborted,omitempty"`
+
+	Tokens   int           `json:"tokens,omitempty"`
+	Cost     float64       `json:"cost,omitempty"`
+	Duration time.Duration `json:"duration_ns,omitempty"`
+	Cached   bool          `json:"cached,omitempty"` // 外部调用是否命中本地缓存
+}
+
+// Matches 判断轨迹是否由指定 URN 与版本的资产运行产生
+func (t *Trace) Matches(urn, version string) bool {
+	return t.URN == urn && t.Version == version
+}
+
+// Transition 描述节点执行完成后的一次跳转
+type Transition struct {
+	Next string
+	Edge string
+	Rule int
+}
+
+// Save 将轨迹以 JSON 格式写入磁盘
+func (t *Trace) Save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// LoadTrace 从磁盘读取一份运行轨迹
+func LoadTrace(path string) (*Trace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// 🧭 读取运行轨迹失败: %v
+		return nil, fmt.Errorf(i18n.T("errors.trace_load_fail"), err)
+	}
+
+	var t Trace
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.trace_load_fail"), err)
+	}
+	return &t, nil
+}
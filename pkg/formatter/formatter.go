@@ -0,0 +1,207 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"gopkg.in/yaml.v3"
+)
+
+// preserveOrder 自由结构中保留作者顺序的字段：JSON Schema 的属性顺序通常即展示顺序
+var preserveOrder = map[string]bool{"schema": true}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Format 将 .runly 文件格式化为规范形式，基于 yaml.Node 语法树进行，保留全部注释：
+//   - 结构体字段按 RunlyProtocol 中的声明顺序排列，x- 扩展字段置于最前，未知字段保持原顺序置于末尾
+//   - 映射类字段 (如 headers、节点 config) 的键按字母序排列，序列元素顺序不变
+//   - 统一两空格缩进与块风格，标识符类字符串不加引号，含空白或占位符的文本及必须加引号的值统一使用双引号，多行文本使用 | 块
+func Format(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.yaml_unmarshal_fail"), err)
+	}
+	if doc.Kind == 0 {
+		// 空文件原样返回
+		return data, nil
+	}
+
+	// 紧贴首个字段的注释视为文件头注释，重排后仍保留在文件顶部
+	root := doc.Content[0]
+	var header string
+	if root.Kind == yaml.MappingNode && len(root.Content) > 0 {
+		header, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+
+	formatNode(&doc, reflect.TypeOf(protocol.RunlyProtocol{}), true)
+
+	if header != "" {
+		first := root.Content[0]
+		first.HeadComment = strings.TrimSuffix(header+"\n"+first.HeadComment, "\n")
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	// 重排可能使别名出现在锚点之前，此时放弃格式化而不是输出无法解析的文件
+	var check yaml.Node
+	if err := yaml.Unmarshal(buf.Bytes(), &check); err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.fmt_anchor_order"), err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formatNode 按对应的 Go 类型递归规整节点，t 为 nil 表示自由结构 (interface{})
+func formatNode(n *yaml.Node, t reflect.Type, sortKeys bool) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, c := range n.Content {
+			formatNode(c, t, sortKeys)
+		}
+
+	case yaml.MappingNode:
+		if len(n.Content) > 0 {
+			n.Style &^= yaml.FlowStyle
+		}
+		if t != nil && t.Kind() == reflect.Struct && t != timeType {
+			formatStruct(n, t)
+			return
+		}
+		var elem reflect.Type
+		if t != nil && t.Kind() == reflect.Map {
+			elem = t.Elem()
+		}
+		if sortKeys {
+			sortPairs(n, func(a, b string) bool { return a < b })
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			formatScalar(n.Content[i])
+			formatNode(n.Content[i+1], elem, sortKeys)
+		}
+
+	case yaml.SequenceNode:
+		if len(n.Content) > 0 {
+			n.Style &^= yaml.FlowStyle
+		}
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for _, c := range n.Content {
+			formatNode(c, elem, sortKeys)
+		}
+
+	case yaml.ScalarNode:
+		formatScalar(n)
+	}
+}
+
+// formatStruct 按结构体字段声明顺序重排映射，并以字段类型递归处理各值
+func formatStruct(n *yaml.Node, t reflect.Type) {
+	fields := yamlFields(t)
+	index := make(map[string]int, len(fields))
+	for i, f := range fields {
+		index[f.name] = i
+	}
+	rank := func(key string) int {
+		if i, ok := index[key]; ok {
+			return i
+		}
+		if strings.HasPrefix(key, "x-") {
+			return -1 // 扩展字段通常用于定义锚点，置于最前以保证锚点先于别名出现
+		}
+		return len(fields)
+	}
+	sortPairs(n, func(a, b string) bool { return rank(a) < rank(b) })
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i]
+		formatScalar(key)
+		var ft reflect.Type
+		if j, ok := index[key.Value]; ok {
+			ft = fields[j].typ
+		}
+		formatNode(n.Content[i+1], ft, !preserveOrder[key.Value])
+	}
+}
+
+type yamlField struct {
+	name string
+	typ  reflect.Type
+}
+
+// yamlFields 返回参与 YAML 序列化的字段 (按声明顺序)
+func yamlFields(t reflect.Type) []yamlField {
+	var fields []yamlField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields = append(fields, yamlField{name: name, typ: f.Type})
+	}
+	return fields
+}
+
+// sortPairs 对映射的键值对稳定排序，键上的注释随键一起移动
+func sortPairs(n *yaml.Node, less func(a, b string) bool) {
+	pairs := make([][2]*yaml.Node, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		pairs = append(pairs, [2]*yaml.Node{n.Content[i], n.Content[i+1]})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return less(pairs[i][0].Value, pairs[j][0].Value) })
+	for i, p := range pairs {
+		n.Content[2*i], n.Content[2*i+1] = p[0], p[1]
+	}
+}
+
+// formatScalar 统一字符串引号风格：标识符类去掉引号，文本与必须加引号的值使用双引号；多行文本使用字面块
+func formatScalar(n *yaml.Node) {
+	if n.Kind != yaml.ScalarNode || n.ShortTag() != "!!str" {
+		return
+	}
+	if strings.Contains(strings.TrimSuffix(n.Value, "\n"), "\n") {
+		n.Style = yaml.LiteralStyle
+		return
+	}
+	if plain(n.Value) && !freeText(n.Value) {
+		n.Style = 0
+	} else {
+		n.Style = yaml.DoubleQuotedStyle
+	}
+}
+
+// freeText 含空白或模板占位符的文本 (如提示词、条件表达式) 统一加双引号，便于阅读
+func freeText(value string) bool {
+	return strings.ContainsAny(value, " \t") || strings.Contains(value, "{{")
+}
+
+// plain 判断字符串能否不加引号输出且仍被解析为同一字符串 (交由编码器判定)
+func plain(value string) bool {
+	out, err := yaml.Marshal(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value})
+	if err != nil || len(out) == 0 {
+		return false
+	}
+	return out[0] != '\'' && out[0] != '"' && !bytes.HasPrefix(out, []byte("|")) && !bytes.HasPrefix(out, []byte(">"))
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+)
+
+// dotShapes 节点类型对应的 Graphviz 形状与样式
+var dotShapes = map[string][2]string{
+	"AI_TASK":    {"box", "rounded"},
+	"SKILL_CALL": {"component", ""},
+	"HITL":       {"parallelogram", ""},
+	"LOGIC_GATE": {"diamond", ""},
+	"TERMINUS":   {"box", "rounded,bold"},
+	"":           {"doublecircle", ""},
+}
+
+// DOT 渲染为 Graphviz DOT，可通过 `dot -Tpng` 等工具转换
+func DOT(g *Graph) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.Title))
+	fmt.Fprintf(&b, "    label=%s;\n    labelloc=t;\n    rankdir=TB;\n", dotQuote(g.Title))
+	b.WriteString("    node [fontname=\"Helvetica\", fontsize=12];\n")
+	b.WriteString("    edge [fontname=\"Helvetica\", fontsize=10];\n\n")
+
+	for _, n := range g.Nodes {
+		shape, ok := dotShapes[n.Type]
+		if !ok {
+			shape = [2]string{"box", ""}
+		}
+		var styles []string
+		if shape[1] != "" {
+			styles = append(styles, shape[1])
+		}
+		attrs := []string{"label=" + dotQuote(nodeLabel(n, "\n")), "shape=" + shape[0]}
+		if n.Start {
+			attrs = append(attrs, `color="#2563eb"`, "penwidth=3")
+		}
+		if g.Overlay {
+			if n.Executed {
+				styles = append(styles, "filled")
+				attrs = append(attrs, `fillcolor="#dcfce7"`)
+			} else if !n.Start {
+				attrs = append(attrs, `color="#9ca3af"`, `fontcolor="#9ca3af"`)
+			}
+		}
+		if len(styles) > 0 {
+			attrs = append(attrs, "style="+dotQuote(strings.Join(styles, ",")))
+		}
+		fmt.Fprintf(&b, "    %s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	b.WriteString("\n")
+
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.Label))
+		}
+		if e.Kind == executor.EdgeFailure {
+			attrs = append(attrs, "style=dashed")
+			if !g.Overlay {
+				attrs = append(attrs, `color="#dc2626"`)
+			}
+		}
+		if g.Overlay {
+			if e.Executed {
+				attrs = append(attrs, `color="#16a34a"`, "penwidth=2.5")
+			} else {
+				attrs = append(attrs, `color="#d1d5db"`, `fontcolor="#9ca3af"`)
+			}
+		}
+		fmt.Fprintf(&b, "    %s -> %s", dotQuote(e.From), dotQuote(e.To))
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote 生成 DOT 双引号字符串
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
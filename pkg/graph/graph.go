@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Terminate 引擎结束执行的保留跳转目标，在图中绘制为终点
+const Terminate = "terminate"
+
+// Node 图中的一个节点
+type Node struct {
+	ID       string
+	Type     string // 节点类型；终点为空
+	Start    bool   // 是否为 topology.start_at
+	Executed bool   // 叠加运行轨迹后是否被执行过
+}
+
+// Edge 图中的一条跳转边
+type Edge struct {
+	From     string
+	To       string
+	Kind     string // on_success | on_failure | rule | terminus
+	Rule     int    // LOGIC_GATE 规则下标，非规则边为 -1
+	Label    string // 规则边显示条件，跳转边显示类型
+	Executed bool
+}
+
+// Graph 协议拓扑的图表示，节点与边保持声明顺序
+type Graph struct {
+	Title   string
+	Nodes   []*Node
+	Edges   []*Edge
+	Overlay bool // 是否叠加了运行轨迹
+}
+
+// Build 将 Topology.Nodes 转换为图：on_success / on_failure / LOGIC_GATE 规则与 TERMINUS 均生成带标签的边，
+// 跳转到 terminate 时追加终点节点
+func Build(proto *protocol.RunlyProtocol) *Graph {
+	g := &Graph{Title: proto.Manifest.Title}
+	if g.Title == "" {
+		g.Title = proto.Manifest.URN
+	}
+
+	terminates := false
+	add := func(from, to, kind string, rule int, label string) {
+		if to == "" {
+			return
+		}
+		if to == Terminate {
+			terminates = true
+		}
+		g.Edges = append(g.Edges, &Edge{From: from, To: to, Kind: kind, Rule: rule, Label: label})
+	}
+
+	for _, n := range proto.Topology.Nodes {
+		g.Nodes = append(g.Nodes, &Node{ID: n.ID, Type: n.Type, Start: n.ID == proto.Topology.StartAt})
+
+		for i, r := range n.Rules {
+			add(n.ID, r.Next, executor.EdgeRule, i, "#"+strconv.Itoa(i+1)+" "+r.Condition)
+		}
+		if n.Type == "TERMINUS" {
+			add(n.ID, Terminate, executor.EdgeTerminus, -1, "")
+		}
+		add(n.ID, n.OnSuccess, executor.EdgeSuccess, -1, executor.EdgeSuccess)
+		add(n.ID, n.OnFailure, executor.EdgeFailure, -1, executor.EdgeFailure)
+	}
+
+	if terminates {
+		g.Nodes = append(g.Nodes, &Node{ID: Terminate})
+	}
+	return g
+}
+
+// ApplyTraces 叠加运行轨迹：标记实际执行过的节点与跳转边
+func (g *Graph) ApplyTraces(traces []*executor.Trace) {
+	g.Overlay = true
+	nodes := make(map[string]*Node, len(g.Nodes))
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+
+	for _, t := range traces {
+		for _, s := range t.Steps {
+			if s.Aborted {
+				continue
+			}
+			if n, ok := nodes[s.NodeID]; ok {
+				n.Executed = true
+			}
+			if s.Next == "" {
+				continue
+			}
+			for _, e := range g.Edges {
+				if e.From == s.NodeID && e.To == s.Next && e.Kind == s.Edge && (e.Kind != executor.EdgeRule || e.Rule == s.Rule) {
+					e.Executed = true
+					if n, ok := nodes[e.To]; ok && e.To == Terminate {
+						n.Executed = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// Node 按 ID 查找节点
+func (g *Graph) Node(id string) *Node {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+)
+
+// mermaidShapes 节点类型对应的 Mermaid 形状 (左右括号)
+var mermaidShapes = map[string][2]string{
+	"AI_TASK":    {"(", ")"},
+	"SKILL_CALL": {"[[", "]]"},
+	"HITL":       {"[/", "/]"},
+	"LOGIC_GATE": {"{", "}"},
+	"TERMINUS":   {"([", "])"},
+	"":           {"((", "))"},
+}
+
+// Mermaid 渲染为 Mermaid flowchart，可直接嵌入 Markdown
+func Mermaid(g *Graph) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntitle: %s\n---\n", g.Title)
+	b.WriteString("flowchart TD\n")
+
+	ids := mermaidIDs(g)
+	for _, n := range g.Nodes {
+		shape, ok := mermaidShapes[n.Type]
+		if !ok {
+			shape = [2]string{"[", "]"}
+		}
+		fmt.Fprintf(&b, "    %s%s\"%s\"%s\n", ids[n.ID], shape[0], mermaidText(nodeLabel(n, "<br/>")), shape[1])
+	}
+
+	var executed, idle []string
+	for i, e := range g.Edges {
+		from, to := ids[e.From], ids[e.To]
+		if to == "" {
+			to = mermaidID(e.To) // 指向未定义节点的边仍然绘制，便于发现断链
+		}
+		arrow := "-->"
+		if e.Kind == executor.EdgeFailure {
+			arrow = "-.->"
+		}
+		if e.Label != "" {
+			fmt.Fprintf(&b, "    %s %s|\"%s\"| %s\n", from, arrow, mermaidText(e.Label), to)
+		} else {
+			fmt.Fprintf(&b, "    %s %s %s\n", from, arrow, to)
+		}
+		if e.Executed {
+			executed = append(executed, fmt.Sprint(i))
+		} else {
+			idle = append(idle, fmt.Sprint(i))
+		}
+	}
+
+	// 样式：起始节点加粗描边，叠加轨迹时执行过的节点与边着色，未执行的置灰
+	b.WriteString("    classDef start stroke:#2563eb,stroke-width:3px\n")
+	for _, n := range g.Nodes {
+		if n.Start {
+			fmt.Fprintf(&b, "    class %s start\n", ids[n.ID])
+		}
+	}
+	if g.Overlay {
+		b.WriteString("    classDef executed fill:#dcfce7,stroke:#16a34a\n")
+		for _, n := range g.Nodes {
+			if n.Executed {
+				fmt.Fprintf(&b, "    class %s executed\n", ids[n.ID])
+			}
+		}
+		if len(executed) > 0 {
+			fmt.Fprintf(&b, "    linkStyle %s stroke:#16a34a,stroke-width:3px\n", strings.Join(executed, ","))
+		}
+		if len(idle) > 0 {
+			fmt.Fprintf(&b, "    linkStyle %s stroke:#d1d5db\n", strings.Join(idle, ","))
+		}
+	}
+	return b.String()
+}
+
+// mermaidIDs 为节点分配合法的 Mermaid 标识 (原 ID 作为标签显示)
+func mermaidIDs(g *Graph) map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	for _, n := range g.Nodes {
+		ids[n.ID] = mermaidID(n.ID)
+	}
+	return ids
+}
+
+func mermaidID(id string) string {
+	var b strings.Builder
+	b.WriteString("n_")
+	for _, r := range id {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			fmt.Fprintf(&b, "_%x_", r)
+		}
+	}
+	return b.String()
+}
+
+// mermaidText 转义引号标签中的特殊字符
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<br/>", "<br/>", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// nodeLabel 节点显示文本：ID 与类型
+func nodeLabel(n *Node, sep string) string {
+	if n.Type == "" {
+		return n.ID
+	}
+	return n.ID + sep + n.Type
+}
@@ -0,0 +1,309 @@
+package graph
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/originbeat-inc/runly-cli/pkg/executor"
+)
+
+// SVG 布局参数
+const (
+	svgMargin    = 40.0
+	svgTitleH    = 36.0
+	svgNodeH     = 52.0
+	svgGateH     = 72.0
+	svgMinNodeW  = 120.0
+	svgCharW     = 7.5
+	svgHGap      = 48.0
+	svgVGap      = 84.0
+	svgLabelMax  = 40
+	svgLoopSpace = 28.0
+)
+
+// 配色：默认、失败分支、起始节点、执行过 (轨迹叠加)、未执行 (轨迹叠加)
+const (
+	colorDefault  = "#374151"
+	colorFailure  = "#dc2626"
+	colorStart    = "#2563eb"
+	colorExecuted = "#16a34a"
+	colorIdle     = "#d1d5db"
+	fillDefault   = "#ffffff"
+	fillExecuted  = "#dcfce7"
+)
+
+// box 节点在画布上的位置 (中心点) 与尺寸
+type box struct {
+	x, y, w, h float64
+	level      int
+}
+
+// SVG 渲染为独立的 SVG 图片：按从起始节点出发的最长路径分层自上而下布局，
+// 向下的跳转绘制为直线，回跳与自环绘制为右侧弧线
+func SVG(g *Graph) string {
+	boxes, width, height := layout(g)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="Helvetica, Arial, sans-serif">`+"\n", width, height, width, height)
+	b.WriteString("  <defs>\n")
+	for _, c := range []string{colorDefault, colorFailure, colorExecuted, colorIdle} {
+		fmt.Fprintf(&b, `    <marker id="arrow-%s" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`+"\n", c[1:], c)
+	}
+	b.WriteString("  </defs>\n")
+	fmt.Fprintf(&b, `  <rect width="100%%" height="100%%" fill="%s"/>`+"\n", fillDefault)
+	fmt.Fprintf(&b, `  <text x="%.0f" y="%.0f" font-size="16" font-weight="bold" text-anchor="middle" fill="%s">%s</text>`+"\n", width/2, svgMargin/2+14, colorDefault, html.EscapeString(g.Title))
+
+	// 1. 先画边，节点覆盖在边之上
+	loops := make(map[string]int)
+	for _, e := range g.Edges {
+		from, ok1 := boxes[e.From]
+		to, ok2 := boxes[e.To]
+		if !ok1 || !ok2 {
+			continue // 指向未定义节点的边由静态校验报告
+		}
+		color, dash, stroke := edgeStyle(g, e)
+
+		var path string
+		var lx, ly float64
+		switch {
+		case to.level > from.level:
+			x1, y1 := from.x, from.y+from.h/2
+			x2, y2 := to.x, to.y-to.h/2
+			path = fmt.Sprintf("M%.1f,%.1f L%.1f,%.1f", x1, y1, x2, y2)
+			lx, ly = (x1+x2)/2, (y1+y2)/2
+		case to.level == from.level && e.From != e.To:
+			// 同层跳转：连接相对的两侧
+			x1, x2 := from.x+from.w/2, to.x-to.w/2
+			if to.x < from.x {
+				x1, x2 = from.x-from.w/2, to.x+to.w/2
+			}
+			path = fmt.Sprintf("M%.1f,%.1f L%.1f,%.1f", x1, from.y, x2, to.y)
+			lx, ly = (x1+x2)/2, from.y-8
+		default:
+			// 回跳与自环：从节点右侧绕出，多条弧线依次外扩
+			loops[e.From]++
+			offset := svgLoopSpace * float64(loops[e.From]+1)
+			x1, y1 := from.x+from.w/2, from.y
+			x2, y2 := to.x+to.w/2, to.y
+			cx := math.Max(x1, x2) + offset
+			if e.From == e.To {
+				y1, y2 = from.y-from.h/4, from.y+from.h/4
+			}
+			path = fmt.Sprintf("M%.1f,%.1f C%.1f,%.1f %.1f,%.1f %.1f,%.1f", x1, y1, cx, y1, cx, y2, x2, y2)
+			lx, ly = cx-offset/4, (y1+y2)/2
+		}
+		fmt.Fprintf(&b, `  <path d="%s" fill="none" stroke="%s" stroke-width="%.1f"%s marker-end="url(#arrow-%s)"/>`+"\n", path, color, stroke, dash, color[1:])
+		if e.Label != "" {
+			fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f" font-size="11" text-anchor="middle" fill="%s" stroke="%s" stroke-width="4" paint-order="stroke">%s</text>`+"\n",
+				lx, ly+4, labelColor(g, e, color), fillDefault, html.EscapeString(truncate(e.Label, svgLabelMax)))
+		}
+	}
+
+	// 2. 画节点
+	for _, n := range g.Nodes {
+		bx := boxes[n.ID]
+		stroke, fill, text, width := colorDefault, fillDefault, colorDefault, 1.5
+		if g.Overlay {
+			if n.Executed {
+				stroke, fill = colorExecuted, fillExecuted
+			} else {
+				stroke, text = colorIdle, "#9ca3af"
+			}
+		}
+		if n.Start {
+			stroke, width = colorStart, 3
+		}
+		b.WriteString("  " + nodeShape(n, bx, stroke, fill, width) + "\n")
+		if n.Type == "" {
+			fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f" font-size="11" text-anchor="middle" fill="%s">%s</text>`+"\n", bx.x, bx.y+4, text, html.EscapeString(n.ID))
+			continue
+		}
+		fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f" font-size="13" font-weight="bold" text-anchor="middle" fill="%s">%s</text>`+"\n", bx.x, bx.y-2, text, html.EscapeString(n.ID))
+		fmt.Fprintf(&b, `  <text x="%.1f" y="%.1f" font-size="10" text-anchor="middle" fill="%s">%s</text>`+"\n", bx.x, bx.y+13, text, n.Type)
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// layout 计算各节点位置，返回节点盒与画布尺寸
+func layout(g *Graph) (map[string]*box, float64, float64) {
+	adj := make(map[string][]string)
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+	}
+
+	// 1. 从起始节点深度优先遍历，识别回边 (指向当前路径上祖先的边) 并记录访问顺序，未能到达的节点依声明顺序另起一轮
+	var order []string
+	visited := make(map[string]bool)
+	onPath := make(map[string]bool)
+	back := make(map[[2]string]bool)
+	var dfs func(id string)
+	dfs = func(id string) {
+		visited[id], onPath[id] = true, true
+		order = append(order, id)
+		for _, next := range adj[id] {
+			if g.Node(next) == nil {
+				continue
+			}
+			if onPath[next] {
+				back[[2]string{id, next}] = true
+			} else if !visited[next] {
+				dfs(next)
+			}
+		}
+		onPath[id] = false
+	}
+	for _, n := range g.Nodes {
+		if n.Start {
+			dfs(n.ID)
+		}
+	}
+	for _, n := range g.Nodes {
+		if !visited[n.ID] {
+			dfs(n.ID)
+		}
+	}
+
+	// 2. 去掉回边后按最长路径分层，使汇合节点 (如 terminate) 位于所有前驱之下
+	levels := make(map[string]int, len(order))
+	for range order {
+		changed := false
+		for _, e := range g.Edges {
+			if g.Node(e.To) == nil || back[[2]string{e.From, e.To}] || e.From == e.To {
+				continue
+			}
+			if levels[e.To] < levels[e.From]+1 {
+				levels[e.To] = levels[e.From] + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// 按层排列，计算每层宽度
+	var rows [][]string
+	for _, id := range order {
+		l := levels[id]
+		for len(rows) <= l {
+			rows = append(rows, nil)
+		}
+		rows[l] = append(rows[l], id)
+	}
+
+	boxes := make(map[string]*box, len(g.Nodes))
+	rowWidths := make([]float64, len(rows))
+	maxWidth := 0.0
+	for l, row := range rows {
+		for i, id := range row {
+			n := g.Node(id)
+			w := math.Max(svgMinNodeW, float64(maxLen(n.ID, n.Type))*svgCharW+32)
+			h := svgNodeH
+			switch n.Type {
+			case "LOGIC_GATE":
+				w, h = w*1.4, svgGateH
+			case "":
+				w, h = 64, 64
+			}
+			boxes[id] = &box{w: w, h: h, level: l}
+			if i > 0 {
+				rowWidths[l] += svgHGap
+			}
+			rowWidths[l] += w
+		}
+		maxWidth = math.Max(maxWidth, rowWidths[l])
+	}
+
+	// 回跳弧线需要额外的右侧空间
+	backEdges := 0
+	for _, e := range g.Edges {
+		if from, to := boxes[e.From], boxes[e.To]; from != nil && to != nil && (to.level < from.level || e.From == e.To) {
+			backEdges++
+		}
+	}
+	width := maxWidth + 2*svgMargin + svgLoopSpace*float64(min(backEdges, 4)+1)
+
+	y := svgMargin + svgTitleH
+	for l, row := range rows {
+		rowH := 0.0
+		for _, id := range row {
+			rowH = math.Max(rowH, boxes[id].h)
+		}
+		x := svgMargin + (maxWidth-rowWidths[l])/2
+		for _, id := range row {
+			bx := boxes[id]
+			bx.x = x + bx.w/2
+			bx.y = y + rowH/2
+			x += bx.w + svgHGap
+		}
+		y += rowH + svgVGap
+	}
+	height := y - svgVGap + svgMargin
+	return boxes, width, height
+}
+
+// nodeShape 按节点类型绘制形状
+func nodeShape(n *Node, b *box, stroke, fill string, width float64) string {
+	left, right, top, bottom := b.x-b.w/2, b.x+b.w/2, b.y-b.h/2, b.y+b.h/2
+	style := fmt.Sprintf(`fill="%s" stroke="%s" stroke-width="%.1f"`, fill, stroke, width)
+	switch n.Type {
+	case "LOGIC_GATE":
+		return fmt.Sprintf(`<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" %s/>`, b.x, top, right, b.y, b.x, bottom, left, b.y, style)
+	case "HITL":
+		skew := 12.0
+		return fmt.Sprintf(`<polygon points="%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f" %s/>`, left+skew, top, right+skew, top, right-skew, bottom, left-skew, bottom, style)
+	case "SKILL_CALL":
+		return fmt.Sprintf(`<g><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" %s/><line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/><line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/></g>`,
+			left, top, b.w, b.h, style, left+8, top, left+8, bottom, stroke, right-8, top, right-8, bottom, stroke)
+	case "TERMINUS":
+		return fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.1f" %s/>`, left, top, b.w, b.h, b.h/2, style)
+	case "":
+		return fmt.Sprintf(`<g><circle cx="%.1f" cy="%.1f" r="%.1f" %s/><circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="%s"/></g>`,
+			b.x, b.y, b.h/2, style, b.x, b.y, b.h/2-4, stroke)
+	default:
+		return fmt.Sprintf(`<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="10" %s/>`, left, top, b.w, b.h, style)
+	}
+}
+
+// edgeStyle 返回边的颜色、虚线属性与线宽
+func edgeStyle(g *Graph, e *Edge) (string, string, float64) {
+	color, dash, width := colorDefault, "", 1.5
+	if e.Kind == executor.EdgeFailure {
+		color, dash = colorFailure, ` stroke-dasharray="6 4"`
+	}
+	if g.Overlay {
+		if e.Executed {
+			color, width = colorExecuted, 3
+		} else {
+			color = colorIdle
+		}
+	}
+	return color, dash, width
+}
+
+// labelColor 未执行的边标签置灰，其余与边同色 (浅灰线条的标签使用深一级的灰色)
+func labelColor(g *Graph, e *Edge, edge string) string {
+	if g.Overlay && !e.Executed {
+		return "#9ca3af"
+	}
+	return edge
+}
+
+func maxLen(values ...string) int {
+	n := 0
+	for _, v := range values {
+		n = max(n, utf8.RuneCountInString(v))
+	}
+	return n
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
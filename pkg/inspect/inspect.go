@@ -0,0 +1,220 @@
+package inspect
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/pkg/graph"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Node 单个节点的数据流视图：引用的资源、读取与写入的变量以及出口边
+type Node struct {
+	Spec      *protocol.Node
+	Skill     *protocol.SkillResource     // SKILL_CALL 引用的技能，未解析时为 nil
+	Knowledge *protocol.KnowledgeResource // AI_TASK 引用的知识库，未解析时为 nil
+	Reads     []string                    // 读取的变量路径，如 inputs.topic、steps.fetch.output
+	Writes    []string                    // 写入的变量路径，如 steps.fetch.output、artifacts.report
+	Edges     []*graph.Edge
+}
+
+// Report 整个拓扑的数据流分析结果，节点保持声明顺序
+type Report struct {
+	Graph *graph.Graph
+	Nodes []*Node
+	index map[string]*Node
+}
+
+// Analyze 按引擎的执行语义推导每个节点读写的变量
+func Analyze(proto *protocol.RunlyProtocol) *Report {
+	r := &Report{Graph: graph.Build(proto), index: make(map[string]*Node)}
+
+	for i := range proto.Topology.Nodes {
+		spec := &proto.Topology.Nodes[i]
+		n := &Node{Spec: spec}
+		reads := newPathSet()
+
+		// 节点配置中的全部 {{...}} 引用
+		collectRefs(spec.Config, reads)
+		for _, rule := range spec.Rules {
+			reads.add(protocol.VarRefs(rule.Condition)...)
+		}
+
+		switch spec.Type {
+		case "SKILL_CALL":
+			ref, _ := spec.Config["skill_ref"].(string)
+			for j := range proto.Skills {
+				if proto.Skills[j].ID == ref {
+					n.Skill = &proto.Skills[j]
+				}
+			}
+			// 技能请求模板在调用时按当前上下文渲染，其引用视为节点读取
+			if n.Skill != nil {
+				reads.add(protocol.VarRefs(n.Skill.Config.Endpoint)...)
+				collectRefs(n.Skill.Config.Headers, reads)
+				collectRefs(n.Skill.Contract.Request, reads)
+			}
+			n.Writes = append(n.Writes, "steps."+spec.ID+".output")
+
+		case "AI_TASK":
+			if ref, _ := spec.Config["knowledge_ref"].(string); ref != "" {
+				for j := range proto.Knowledge {
+					if proto.Knowledge[j].ID == ref {
+						n.Knowledge = &proto.Knowledge[j]
+					}
+				}
+				if n.Knowledge != nil {
+					reads.add(protocol.VarRefs(n.Knowledge.Config.Endpoint)...)
+					collectRefs(n.Knowledge.Config.Headers, reads)
+					if target := n.Knowledge.Injection.TargetVariable; target != "" {
+						n.Writes = append(n.Writes, "knowledge."+target)
+					}
+				}
+			}
+			n.Writes = append(n.Writes, "steps."+spec.ID+".output")
+
+		case "TERMINUS":
+			// data_source 是不带花括号的变量路径
+			if src, _ := spec.Config["data_source"].(string); strings.TrimSpace(src) != "" {
+				reads.add(strings.TrimSpace(src))
+			}
+			if ref, _ := spec.Config["artifact_ref"].(string); ref != "" {
+				n.Writes = append(n.Writes, "artifacts."+ref)
+			}
+		}
+
+		n.Reads = reads.list
+		for _, e := range r.Graph.Edges {
+			if e.From == spec.ID {
+				n.Edges = append(n.Edges, e)
+			}
+		}
+		r.Nodes = append(r.Nodes, n)
+		r.index[spec.ID] = n
+	}
+	return r
+}
+
+// Node 按 ID 查找节点
+func (r *Report) Node(id string) *Node {
+	return r.index[id]
+}
+
+// IDs 返回全部节点 ID (声明顺序)
+func (r *Report) IDs() []string {
+	ids := make([]string, 0, len(r.Nodes))
+	for _, n := range r.Nodes {
+		ids = append(ids, n.Spec.ID)
+	}
+	return ids
+}
+
+// Producers 返回写入了 id 节点所读取变量的上游节点
+func (r *Report) Producers(id string) []string {
+	target := r.index[id]
+	if target == nil {
+		return nil
+	}
+	var ids []string
+	for _, n := range r.Nodes {
+		if n != target && flows(n.Writes, target.Reads) {
+			ids = append(ids, n.Spec.ID)
+		}
+	}
+	return ids
+}
+
+// Consumers 返回读取了 id 节点产出的下游节点
+func (r *Report) Consumers(id string) []string {
+	source := r.index[id]
+	if source == nil {
+		return nil
+	}
+	var ids []string
+	for _, n := range r.Nodes {
+		if n != source && flows(source.Writes, n.Reads) {
+			ids = append(ids, n.Spec.ID)
+		}
+	}
+	return ids
+}
+
+// Inputs 返回节点读取的 Dictionary 输入参数名
+func (n *Node) Inputs() []string {
+	var names []string
+	for _, p := range n.Reads {
+		if parts := strings.Split(p, "."); len(parts) >= 2 && parts[0] == "inputs" {
+			names = append(names, parts[1])
+		}
+	}
+	return names
+}
+
+// flows 判断 writes 中是否有变量被 reads 读取 (读取路径可以深入写入值的内部字段)
+func flows(writes, reads []string) bool {
+	for _, w := range writes {
+		for _, rd := range reads {
+			if rd == w || strings.HasPrefix(rd, w+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// collectRefs 递归扫描配置值中的字符串，收集变量引用；映射按键排序保证输出稳定
+func collectRefs(v interface{}, out *pathSet) {
+	switch val := v.(type) {
+	case string:
+		out.add(protocol.VarRefs(val)...)
+	case map[string]interface{}:
+		for _, k := range sortedKeys(val) {
+			collectRefs(val[k], out)
+		}
+	case map[string]string:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out.add(protocol.VarRefs(val[k])...)
+		}
+	case []interface{}:
+		for _, item := range val {
+			collectRefs(item, out)
+		}
+	case nil:
+	default:
+		out.add(protocol.VarRefs(fmt.Sprintf("%v", val))...)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// pathSet 保持插入顺序的去重集合
+type pathSet struct {
+	list []string
+	seen map[string]bool
+}
+
+func newPathSet() *pathSet {
+	return &pathSet{seen: make(map[string]bool)}
+}
+
+func (s *pathSet) add(paths ...string) {
+	for _, p := range paths {
+		if !s.seen[p] {
+			s.seen[p] = true
+			s.list = append(s.list, p)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package jcs
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Marshal 按 RFC 8785 (JSON Canonicalization Scheme) 序列化通用 JSON 值
+// 支持 nil、bool、string、float64 及各整数类型、map[string]interface{}、[]interface{}
+//
+// 规则：对象成员按键的 UTF-16 码元排序；字符串只转义引号、反斜杠与控制字符；
+// 数字按 ECMAScript Number.prototype.toString 的最短往返形式输出；不含任何空白
+func Marshal(v interface{}) ([]byte, error) {
+	var b strings.Builder
+	if err := encode(&b, v); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+func encode(b *strings.Builder, v interface{}) error {
+	switch val := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(val))
+	case string:
+		writeString(b, val)
+	case float64:
+		s, err := formatNumber(val)
+		if err != nil {
+			return err
+		}
+		b.WriteString(s)
+	case float32:
+		return encode(b, float64(val))
+	case int:
+		return encode(b, float64(val))
+	case int64:
+		return encode(b, float64(val))
+	case int32:
+		return encode(b, float64(val))
+	case uint:
+		return encode(b, float64(val))
+	case uint64:
+		return encode(b, float64(val))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeString(b, k)
+			b.WriteByte(':')
+			if err := encode(b, val[k]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case []interface{}:
+		b.WriteByte('[')
+		for i, item := range val {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := encode(b, item); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	default:
+		return fmt.Errorf("jcs: unsupported type %T", v)
+	}
+	return nil
+}
+
+// formatNumber 实现 ECMAScript 数字序列化：1e-6 <= |f| < 1e21 使用定点写法，其余使用指数写法
+func formatNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", fmt.Errorf("jcs: %v is not a valid JSON number", f)
+	}
+	if f == 0 {
+		return "0", nil // 包括 -0
+	}
+
+	abs := math.Abs(f)
+	if abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+
+	// Go 输出 "1e+21"、"1.5e-07"，ECMAScript 要求指数不补零
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(s, "e")
+	return mantissa + "e" + exp[:1] + strings.TrimLeft(exp[1:], "0"), nil
+}
+
+// writeString 按 RFC 8785 转义字符串：仅转义 "、\ 与 U+0000..U+001F，其余字符原样输出 (UTF-8)
+func writeString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+// lessUTF16 按 UTF-16 码元比较两个字符串 (RFC 8785 §3.2.3 的键排序规则)
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+	return len(ua) < len(ub)
+}
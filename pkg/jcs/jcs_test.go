@@ -0,0 +1,120 @@
+package jcs
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+// RFC 8785 附录 B：IEEE 754 位模式与 ECMAScript 序列化结果
+func TestNumbers(t *testing.T) {
+	cases := []struct {
+		bits uint64
+		want string
+	}{
+		{0x0000000000000000, "0"},
+		{0x8000000000000000, "0"}, // -0
+		{0x0000000000000001, "5e-324"},
+		{0x8000000000000001, "-5e-324"},
+		{0x7fefffffffffffff, "1.7976931348623157e+308"},
+		{0xffefffffffffffff, "-1.7976931348623157e+308"},
+		{0x4340000000000000, "9007199254740992"},
+		{0xc340000000000000, "-9007199254740992"},
+		{0x4430000000000000, "295147905179352830000"},
+		{0x44b52d02c7e14af5, "9.999999999999997e+22"},
+		{0x44b52d02c7e14af6, "1e+23"},
+		{0x44b52d02c7e14af7, "1.0000000000000001e+23"},
+		{0x444b1ae4d6e2ef4e, "999999999999999700000"},
+		{0x444b1ae4d6e2ef4f, "999999999999999900000"},
+		{0x444b1ae4d6e2ef50, "1e+21"},
+		{0x3eb0c6f7a0b5ed8c, "9.999999999999997e-7"},
+		{0x3eb0c6f7a0b5ed8d, "0.000001"},
+		{0x41b3de4355555553, "333333333.3333332"},
+		{0x41b3de4355555554, "333333333.33333325"},
+		{0x41b3de4355555555, "333333333.3333333"},
+		{0x41b3de4355555556, "333333333.3333334"},
+		{0x41b3de4355555557, "333333333.33333343"},
+		{0xbecbf647612f3696, "-0.0000033333333333333333"},
+		{0x43143ff3c1cb0959, "1424953923781206.2"},
+	}
+	for _, c := range cases {
+		got, err := Marshal(math.Float64frombits(c.bits))
+		if err != nil {
+			t.Errorf("%016x: %v", c.bits, err)
+			continue
+		}
+		if string(got) != c.want {
+			t.Errorf("%016x = %s, want %s", c.bits, got, c.want)
+		}
+	}
+
+	// 十进制写法的输入同样得到最短往返形式
+	for in, want := range map[float64]string{1e21: "1e+21", 1e-7: "1e-7", 333333333.33333329: "333333333.3333333", 1e-6: "0.000001"} {
+		if got, _ := Marshal(in); string(got) != want {
+			t.Errorf("%v = %s, want %s", in, got, want)
+		}
+	}
+
+	for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := Marshal(bad); err == nil {
+			t.Errorf("%v should be rejected", bad)
+		}
+	}
+}
+
+// RFC 8785 §3.2.3：对象成员按键的 UTF-16 码元排序 (而非 UTF-8 字节或码点)
+func TestKeyOrdering(t *testing.T) {
+	input := `{
+		"€": "Euro Sign",
+		"\r": "Carriage Return",
+		"דּ": "Hebrew Letter Dalet With Dagesh",
+		"1": "One",
+		"😀": "Emoji: Grinning Face",
+		"\u0080": "Control",
+		"ö": "Latin Small Letter O With Diaeresis"
+	}`
+	want := "{" +
+		`"\r":"Carriage Return",` +
+		`"1":"One",` +
+		"\"\u0080\":\"Control\"," +
+		"\"ö\":\"Latin Small Letter O With Diaeresis\"," +
+		"\"€\":\"Euro Sign\"," +
+		"\"\U0001F600\":\"Emoji: Grinning Face\"," +
+		"\"דּ\":\"Hebrew Letter Dalet With Dagesh\"" +
+		"}"
+	assertCanonical(t, input, want)
+}
+
+// RFC 8785 §3.2.2.2：只转义引号、反斜杠与 U+0000..U+001F，短形式优先，其余为小写 \u00xx
+func TestStringEscaping(t *testing.T) {
+	input := `["\u0000\u0007\b\t\n\u000b\f\r\u001f", "\"\\/", "\u007f €"]`
+	want := `["\u0000\u0007\b\t\n\u000b\f\r\u001f","\"\\/",` + "\"\u007f €\"]"
+	assertCanonical(t, input, want)
+}
+
+// RFC 8785 §3.2.4 的完整示例
+func TestExample(t *testing.T) {
+	input := `{
+		"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+		"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+		"literals": [null, true, false]
+	}`
+	want := `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],` +
+		`"string":"€$\u000f\nA'B\"\\\\\"/"}`
+	assertCanonical(t, input, want)
+}
+
+func assertCanonical(t *testing.T, input, want string) {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,97 @@
+package keystore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/pkg/jcs"
+)
+
+// 声明类型：轮换与吊销声明以 JCS 序列化后签名，身份服务器与其他验证器可独立复现
+const (
+	StatementRotation   = "runly-key-rotation/v1"
+	StatementRevocation = "runly-key-revocation/v1"
+)
+
+// HistoryEntry 轮换下来的旧密钥：只保留公钥，用于验证轮换前签名的存量资产
+type HistoryEntry struct {
+	MeID      string     `json:"me_id"`
+	PublicKey string     `json:"public_key"`
+	RetiredAt time.Time  `json:"retired_at"`           // 轮换时间，此后的签名不再接受
+	RevokedAt *time.Time `json:"revoked_at,omitempty"` // 吊销时间，此后的签名一律拒绝
+	Statement string     `json:"statement,omitempty"`  // 轮换声明 (JCS)
+	Signature string     `json:"signature,omitempty"`  // 旧密钥对轮换声明的签名
+}
+
+// History 本地密钥历史 (~/.runly/keys/<profile>.history.json)
+type History struct {
+	Path string         `json:"-"`
+	Keys []HistoryEntry `json:"keys"`
+}
+
+// HistoryPath 密钥文件对应的历史文件路径
+func HistoryPath(keyPath string) string {
+	return strings.TrimSuffix(keyPath, filepath.Ext(keyPath)) + ".history.json"
+}
+
+// LoadHistory 读取密钥历史，文件不存在时返回空历史
+func LoadHistory(path string) (*History, error) {
+	h := &History{Path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Save 写回磁盘
+func (h *History) Save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(h.Path, data, 0600)
+}
+
+// Find 按公钥查找历史密钥
+func (h *History) Find(publicKey string) *HistoryEntry {
+	for i := range h.Keys {
+		if strings.EqualFold(h.Keys[i].PublicKey, publicKey) {
+			return &h.Keys[i]
+		}
+	}
+	return nil
+}
+
+// RotationStatement 轮换声明：由旧密钥签名，表明 MeID 自 at 起改用新公钥
+func RotationStatement(meID, oldKey, newKey string, at time.Time) ([]byte, error) {
+	return jcs.Marshal(map[string]interface{}{
+		"type":       StatementRotation,
+		"me_id":      meID,
+		"old_key":    oldKey,
+		"new_key":    newKey,
+		"rotated_at": at.UTC().Format(time.RFC3339),
+	})
+}
+
+// RevocationStatement 吊销声明：由当前密钥签名，表明公钥自 at 起不再可信
+func RevocationStatement(meID, publicKey string, at time.Time) ([]byte, error) {
+	return jcs.Marshal(map[string]interface{}{
+		"type":       StatementRevocation,
+		"me_id":      meID,
+		"public_key": publicKey,
+		"revoked_at": at.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,123 @@
+package keystore
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/originbeat-inc/runly-cli/pkg/crypto"
+)
+
+// Format 密钥文件格式标识，export 导出的文件与本地存储格式相同
+const Format = "runly-key"
+
+// ErrKeyLocked 未提供签名私钥口令
+var ErrKeyLocked = errors.New("signing key is locked (set RUNLY_KEY_PASSPHRASE)")
+
+// ErrKeyMismatch 解密得到的私钥与记录的公钥不匹配
+var ErrKeyMismatch = errors.New("decrypted key does not match the recorded public key")
+
+// PassphraseFunc 获取签名私钥口令，CLI 可替换为交互式输入
+var PassphraseFunc = func() ([]byte, error) {
+	if p := os.Getenv("RUNLY_KEY_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	}
+	return nil, ErrKeyLocked
+}
+
+// File 口令加密的签名私钥文件：公钥与 MeID 明文保存，Ed25519 种子经 scrypt + AES-256-GCM 加密
+type File struct {
+	Format    string            `json:"format"`
+	Version   int               `json:"version"`
+	MeID      string            `json:"me_id"`
+	PublicKey string            `json:"public_key"`
+	Box       *crypto.SealedBox `json:"box"`
+}
+
+// Seal 使用口令加密 Ed25519 种子 (hex)，种子须与公钥匹配
+func Seal(meID, publicKeyHex, seedHex string, passphrase []byte) (*File, error) {
+	if err := checkPair(publicKeyHex, seedHex); err != nil {
+		return nil, err
+	}
+	box, err := crypto.SealWithPassphrase([]byte(seedHex), passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Format: Format, Version: 1, MeID: meID, PublicKey: publicKeyHex, Box: box}, nil
+}
+
+// Open 使用口令解密，返回 Ed25519 种子 (hex)
+func (f *File) Open(passphrase []byte) (string, error) {
+	plaintext, err := crypto.OpenWithPassphrase(f.Box, passphrase)
+	if err != nil {
+		return "", err
+	}
+	seedHex := string(plaintext)
+	if err := checkPair(f.PublicKey, seedHex); err != nil {
+		return "", err
+	}
+	return seedHex, nil
+}
+
+// Load 读取密钥文件
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, err
+	}
+	if f.Format != Format || f.Box == nil {
+		return nil, fmt.Errorf("%s: not a runly key file", path)
+	}
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Validate 检查密钥文件的版本、公钥与加密参数；导入的文件可能来自不可信来源，须在解密前校验
+func (f *File) Validate() error {
+	if f.Version != 1 {
+		return fmt.Errorf("unsupported key file version %d", f.Version)
+	}
+	if pub, err := hex.DecodeString(f.PublicKey); err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid Ed25519 public key %q", f.PublicKey)
+	}
+	return f.Box.Validate()
+}
+
+// Save 写出密钥文件 (目录 0700，文件 0600)
+func (f *File) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile 不会修改已存在文件的权限
+	return os.Chmod(path, 0600)
+}
+
+// checkPair 检查种子派生的公钥与记录的公钥一致
+func checkPair(publicKeyHex, seedHex string) error {
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("invalid Ed25519 seed (expected %d hex-encoded bytes)", ed25519.SeedSize)
+	}
+	pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+	if hex.EncodeToString(pub) != publicKeyHex {
+		return ErrKeyMismatch
+	}
+	return nil
+}
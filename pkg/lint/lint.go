@@ -0,0 +1,224 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Rules 最佳实践规则，与 protocol 的校验规则共用 .runlyrc 与内联注释配置
+var Rules = []protocol.Rule{
+	{Code: "LINT001", Severity: protocol.SeverityWarning, Summary: "AI_TASK prompts should reference at least one input"},
+	{Code: "LINT002", Severity: protocol.SeverityWarning, Summary: "SKILL_CALL nodes should define on_failure"},
+	{Code: "LINT003", Severity: protocol.SeverityWarning, Summary: "skill and knowledge endpoints should use https://"},
+	{Code: "LINT004", Severity: protocol.SeverityInfo, Summary: "skills, knowledge and artifacts should have a description"},
+	{Code: "LINT005", Severity: protocol.SeverityError, Summary: "credentials in headers must use {{secret.*}} or {{env.*}} references"},
+	{Code: "LINT006", Severity: protocol.SeverityError, Summary: "commerce.royalty shares must sum to 1"},
+	{Code: "LINT007", Severity: protocol.SeverityWarning, Summary: "declared skills should be used by a node"},
+	{Code: "LINT008", Severity: protocol.SeverityWarning, Summary: "declared knowledge resources should be used by a node"},
+	{Code: "LINT009", Severity: protocol.SeverityWarning, Summary: "declared inputs should be referenced"},
+}
+
+func init() {
+	protocol.RegisterRules(Rules...)
+}
+
+// inputRefRegex 匹配 {{inputs.name}} 引用
+var inputRefRegex = regexp.MustCompile(`\{\{\s*inputs\.([\w\-]+)`)
+
+// shareTolerance 分成比例求和允许的浮点误差
+const shareTolerance = 1e-6
+
+// Lint 对协议执行最佳实践检查，返回使用规则默认级别的诊断
+func Lint(proto *protocol.RunlyProtocol) protocol.Diagnostics {
+	l := &linter{proto: proto}
+
+	// 1. 节点级检查：提示词引用与失败分支
+	l.checkNodes()
+
+	// 2. 资源级检查：传输安全、凭证硬编码与描述完整性
+	l.checkResources()
+
+	// 3. 商业条款检查：分成比例
+	l.checkRoyalty()
+
+	// 4. 未使用的技能、知识库与输入参数
+	l.checkUnused()
+
+	return l.diags
+}
+
+// Run 合并协议校验与最佳实践检查，并应用 .runlyrc 与内联注释配置
+func Run(proto *protocol.RunlyProtocol) (protocol.Diagnostics, error) {
+	cfg, err := protocol.LoadRuleConfig(proto.Source)
+	if err != nil {
+		return nil, err
+	}
+	diags := append(protocol.Analyze(proto), Lint(proto)...)
+	return diags.Configure(cfg), nil
+}
+
+// linter 最佳实践检查过程中的共享状态
+type linter struct {
+	proto *protocol.RunlyProtocol
+	diags protocol.Diagnostics
+}
+
+// report 记录一条诊断，返回该诊断以便补充修复建议
+func (l *linter) report(code string, pos protocol.Position, key string, args ...interface{}) *protocol.Diagnostic {
+	d := protocol.NewRuleDiagnostic(code, pos, i18n.T(key), args...)
+	l.diags = append(l.diags, d)
+	return d
+}
+
+// checkNodes 检查 AI_TASK 提示词与 SKILL_CALL 失败分支
+func (l *linter) checkNodes() {
+	for _, node := range l.proto.Topology.Nodes {
+		switch node.Type {
+		case "AI_TASK":
+			prompt, _ := node.Config["prompt"].(string)
+			if !inputRefRegex.MatchString(prompt) {
+				// 💬 AI_TASK [%s] 的提示词未引用任何输入参数
+				l.report("LINT001", node.At("config.prompt"), "errors.lint_prompt_no_input", node.ID).
+					Suggestion = i18n.T("errors.hint_prompt_input")
+			}
+		case "SKILL_CALL":
+			if node.OnFailure == "" {
+				// 🧯 SKILL_CALL [%s] 未定义 on_failure
+				l.report("LINT002", node.Pos, "errors.lint_no_on_failure", node.ID).
+					Suggestion = i18n.T("errors.hint_on_failure")
+			}
+		}
+	}
+}
+
+// checkResources 检查技能与知识库的传输协议、请求头凭证与描述
+func (l *linter) checkResources() {
+	for _, s := range l.proto.Skills {
+		l.checkEndpoint(s.ID, s.Config.Endpoint, s.At("config.endpoint"))
+		l.checkHeaders(s.ID, s.Config.Headers, s.Located)
+		l.checkDescription("skills", s.ID, s.Description, s.Pos)
+	}
+	for _, k := range l.proto.Knowledge {
+		l.checkEndpoint(k.ID, k.Config.Endpoint, k.At("config.endpoint"))
+		l.checkHeaders(k.ID, k.Config.Headers, k.Located)
+		l.checkDescription("knowledge", k.ID, k.Description, k.Pos)
+	}
+	for _, a := range l.proto.Dictionary.Artifacts {
+		l.checkDescription("artifacts", a.ID, a.Description, a.Pos)
+	}
+}
+
+// checkEndpoint 明文 http:// 端点 (本机地址除外) 会暴露请求内容与凭证
+func (l *linter) checkEndpoint(id, endpoint string, pos protocol.Position) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "http" || isLoopback(u.Hostname()) {
+		return
+	}
+	// 🔓 [%s] 使用明文 HTTP 端点: %s
+	l.report("LINT003", pos, "errors.lint_plain_http", id, endpoint).
+		Suggestion = i18n.T("errors.hint_https")
+}
+
+// checkHeaders 凭证类请求头必须通过密钥占位符引用
+func (l *linter) checkHeaders(id string, headers map[string]string, loc protocol.Located) {
+	for _, name := range sortedKeys(headers) {
+		value := headers[name]
+		if !protocol.IsSensitiveHeader(name) || value == "" || protocol.IsSecretOnly(value) {
+			continue
+		}
+		// 🔑 [%s] 的请求头 %s 中硬编码了凭证
+		l.report("LINT005", loc.At("config.headers."+name), "errors.lint_hardcoded_token", id, name).
+			Suggestion = i18n.T("errors.hint_secret_ref")
+	}
+}
+
+// checkDescription 资源与交付物应附带描述，便于 Hub 展示与审阅
+func (l *linter) checkDescription(domain, id, description string, pos protocol.Position) {
+	if strings.TrimSpace(description) != "" {
+		return
+	}
+	// 📝 %s [%s] 缺少 description
+	l.report("LINT004", pos, "errors.lint_no_description", domain, id)
+}
+
+// checkRoyalty 创作者与平台分成之和必须为 1，未声明 royalty 时跳过
+func (l *linter) checkRoyalty() {
+	r := l.proto.Commerce.Royalty
+	if !l.proto.Commerce.Has("royalty") && r == (protocol.Royalty{}) {
+		return
+	}
+	sum := r.CreatorShare + r.PlatformShare
+	if math.Abs(sum-1) <= shareTolerance {
+		return
+	}
+	// 💰 分成比例之和为 %g，应为 1
+	l.report("LINT006", l.proto.Commerce.At("royalty"), "errors.lint_royalty_sum", roundShare(sum)).
+		Suggestion = fmt.Sprintf(i18n.T("errors.hint_royalty"), r.CreatorShare, roundShare(1-r.CreatorShare))
+}
+
+// checkUnused 检查未被任何节点使用的技能、知识库与未被引用的输入参数
+func (l *linter) checkUnused() {
+	usedSkills := make(map[string]bool)
+	usedKB := make(map[string]bool)
+	for _, node := range l.proto.Topology.Nodes {
+		if ref, ok := node.Config["skill_ref"].(string); ok {
+			usedSkills[ref] = true
+		}
+		if ref, ok := node.Config["knowledge_ref"].(string); ok {
+			usedKB[ref] = true
+		}
+	}
+
+	for _, s := range l.proto.Skills {
+		if !usedSkills[s.ID] {
+			// 🧹 技能 [%s] 未被任何节点使用
+			l.report("LINT007", s.Pos, "errors.lint_unused_skill", s.ID)
+		}
+	}
+	for _, k := range l.proto.Knowledge {
+		if !usedKB[k.ID] {
+			// 🧹 知识库 [%s] 未被任何节点使用
+			l.report("LINT008", k.Pos, "errors.lint_unused_kb", k.ID)
+		}
+	}
+
+	// 输入参数可在节点配置、网关条件与技能契约中引用，统一扫描序列化后的协议
+	data, _ := json.Marshal(l.proto)
+	usedInputs := make(map[string]bool)
+	for _, m := range inputRefRegex.FindAllStringSubmatch(string(data), -1) {
+		usedInputs[m[1]] = true
+	}
+	for _, in := range l.proto.Dictionary.Inputs {
+		if !usedInputs[in.Name] {
+			// 🧹 输入参数 [%s] 未被引用
+			l.report("LINT009", in.Pos, "errors.lint_unused_input", in.Name)
+		}
+	}
+}
+
+// 辅助逻辑
+// roundShare 去除浮点运算误差，便于展示
+func roundShare(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+func isLoopback(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"path/filepath"
+
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// SARIF 2.1.0 输出，供 GitHub Code Scanning 等平台直接导入
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+type SarifRun struct {
+	Tool    SarifTool     `json:"tool"`
+	Results []SarifResult `json:"results"`
+}
+
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+type SarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     SarifMessage       `json:"shortDescription"`
+	DefaultConfiguration SarifConfiguration `json:"defaultConfiguration"`
+}
+
+type SarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+type SarifResult struct {
+	RuleID    string          `json:"ruleId,omitempty"`
+	Level     string          `json:"level"`
+	Message   SarifMessage    `json:"message"`
+	Locations []SarifLocation `json:"locations,omitempty"`
+}
+
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           *SarifRegion          `json:"region,omitempty"`
+}
+
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// ToSARIF 将诊断转换为 SARIF 日志，规则表包含全部已注册规则
+func ToSARIF(diags protocol.Diagnostics, toolVersion string) *SarifLog {
+	driver := SarifDriver{
+		Name:           "runly-cli",
+		Version:        toolVersion,
+		InformationURI: "https://github.com/originbeat-inc/runly-cli",
+	}
+	for _, r := range protocol.Rules {
+		driver.Rules = append(driver.Rules, SarifRule{
+			ID:                   r.Code,
+			ShortDescription:     SarifMessage{Text: r.Summary},
+			DefaultConfiguration: SarifConfiguration{Level: sarifLevel(r.Severity)},
+		})
+	}
+
+	results := make([]SarifResult, 0, len(diags))
+	for _, d := range diags {
+		text := d.Message
+		if d.Suggestion != "" {
+			text += " (" + d.Suggestion + ")"
+		}
+		res := SarifResult{RuleID: d.Code, Level: sarifLevel(d.Severity), Message: SarifMessage{Text: text}}
+		if d.Pos.File != "" {
+			loc := SarifPhysicalLocation{ArtifactLocation: SarifArtifactLocation{URI: filepath.ToSlash(d.Pos.File)}}
+			if d.Pos.IsValid() {
+				loc.Region = &SarifRegion{StartLine: d.Pos.Line, StartColumn: d.Pos.Column}
+			}
+			res.Locations = []SarifLocation{{PhysicalLocation: loc}}
+		}
+		results = append(results, res)
+	}
+
+	return &SarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []SarifRun{{Tool: SarifTool{Driver: driver}, Results: results}},
+	}
+}
+
+// sarifLevel 将诊断级别映射为 SARIF level (error | warning | note)
+func sarifLevel(s protocol.Severity) string {
+	switch s {
+	case protocol.SeverityError:
+		return "error"
+	case protocol.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,171 @@
+package migrate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/originbeat-inc/runly-cli/pkg/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// legacyVersion 未声明 spec_version 的协议所属的规范版本
+const legacyVersion = "1.0"
+
+// Migration 一条协议迁移：在 yaml.Node 语法树上原地变换，保留注释与字段顺序
+type Migration struct {
+	ID          string // 稳定标识，同时用作测试夹具目录名
+	From        string // 起始规范版本 (MAJOR.MINOR)
+	To          string // 目标规范版本
+	Description string
+	Apply       func(root *yaml.Node) error // root 为文档根映射节点
+}
+
+// registry 已注册的迁移，同一版本区间内按注册顺序执行
+var registry []Migration
+
+// Register 注册一条迁移，通常在 init 中调用
+func Register(m Migration) {
+	registry = append(registry, m)
+}
+
+// Migrations 返回全部已注册迁移
+func Migrations() []Migration {
+	return append([]Migration(nil), registry...)
+}
+
+// Plan 计算从 from 升级到 to 需要依次执行的迁移，版本号可写作 1.1 或 1.1.0
+func Plan(from, to string) ([]Migration, error) {
+	from, err := normalizeVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	if to, err = normalizeVersion(to); err != nil {
+		return nil, err
+	}
+	fromV, toV := semver.MustParse(from), semver.MustParse(to)
+	if toV.LessThan(fromV) {
+		// ⏪ 不支持降级
+		return nil, fmt.Errorf(i18n.T("errors.migrate_downgrade"), from, to)
+	}
+
+	var plan []Migration
+	current := from
+	for current != to {
+		next := ""
+		for _, m := range registry {
+			if m.From == current {
+				plan = append(plan, m)
+				next = m.To
+			}
+		}
+		if next == "" || semver.MustParse(to).LessThan(semver.MustParse(next)) {
+			// 🧭 找不到迁移路径
+			return nil, fmt.Errorf(i18n.T("errors.migrate_no_path"), from, to)
+		}
+		current = next
+	}
+	return plan, nil
+}
+
+// Result 一次迁移的结果
+type Result struct {
+	From    string
+	To      string
+	Applied []Migration
+	Output  []byte // 迁移后的 YAML；未执行任何迁移时与输入一致
+}
+
+// Run 解析协议 YAML，按序执行迁移并重新编码，to 为空时迁移到 CLI 支持的最新规范版本
+func Run(data []byte, to string) (*Result, error) {
+	if to == "" || to == "latest" {
+		to = protocol.SpecVersion
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf(i18n.T("errors.yaml_unmarshal_fail"), err)
+	}
+	root := documentRoot(&doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf(i18n.T("errors.yaml_unmarshal_fail"), "document root is not a mapping")
+	}
+
+	from, err := DetectVersion(root)
+	if err != nil {
+		return nil, err
+	}
+	if to, err = normalizeVersion(to); err != nil {
+		return nil, err
+	}
+	plan, err := Plan(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{From: from, To: to, Applied: plan, Output: data}
+	if len(plan) == 0 {
+		return res, nil
+	}
+
+	for _, m := range plan {
+		if err := m.Apply(root); err != nil {
+			return nil, fmt.Errorf("%s: %w", m.ID, err)
+		}
+		setSpecVersion(root, m.To)
+	}
+
+	out, err := encode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	res.Output = out
+	return res, nil
+}
+
+// DetectVersion 读取根节点的 spec_version 并规整为 MAJOR.MINOR，缺省为 1.0
+func DetectVersion(root *yaml.Node) (string, error) {
+	n := mapGet(root, "spec_version")
+	if n == nil || n.Value == "" {
+		return legacyVersion, nil
+	}
+	return normalizeVersion(n.Value)
+}
+
+// normalizeVersion 将规范版本号规整为 MAJOR.MINOR (1.1.0 → 1.1)
+func normalizeVersion(s string) (string, error) {
+	v, err := semver.Parse(s)
+	if err != nil {
+		return "", fmt.Errorf(i18n.T("errors.spec_version_invalid"), s)
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor), nil
+}
+
+// setSpecVersion 写入 spec_version，缺失时作为首个字段插入 (并承接原首字段的头部注释)
+func setSpecVersion(root *yaml.Node, version string) {
+	if n := mapGet(root, "spec_version"); n != nil {
+		n.Value, n.Tag, n.Style = version, "!!str", yaml.DoubleQuotedStyle
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "spec_version"}
+	val := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: version, Style: yaml.DoubleQuotedStyle}
+	if len(root.Content) > 0 {
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, val}, root.Content...)
+}
+
+// encode 以两空格缩进重新编码文档
+func encode(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,105 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+	"github.com/originbeat-inc/runly-cli/pkg/textdiff"
+	"gopkg.in/yaml.v3"
+)
+
+// 每条迁移在 testdata/<ID>/ 下有一组 before/after 夹具
+func TestMigrations(t *testing.T) {
+	for _, m := range Migrations() {
+		t.Run(m.ID, func(t *testing.T) {
+			before, after := fixtures(t, m.ID)
+
+			var doc yaml.Node
+			if err := yaml.Unmarshal(before, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := m.Apply(documentRoot(&doc)); err != nil {
+				t.Fatalf("apply: %v", err)
+			}
+			got, err := encode(&doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertYAML(t, string(after), string(got))
+		})
+	}
+}
+
+// 完整迁移链：写入 spec_version 并保留文件头注释
+func TestRunChain(t *testing.T) {
+	before, after := fixtures(t, "chain")
+
+	res, err := Run(before, "latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.From != "1.0" || res.To != protocol.SpecVersion {
+		t.Fatalf("got %s -> %s", res.From, res.To)
+	}
+	if len(res.Applied) != len(Migrations()) {
+		t.Fatalf("applied %d migrations, want %d", len(res.Applied), len(Migrations()))
+	}
+	assertYAML(t, string(after), string(res.Output))
+
+	// 再次迁移应为空操作
+	again, err := Run(res.Output, "latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Applied) != 0 || string(again.Output) != string(res.Output) {
+		t.Fatalf("second run should be a no-op, applied %d", len(again.Applied))
+	}
+}
+
+func TestPlan(t *testing.T) {
+	if _, err := Plan("1.1", "1.0"); err == nil {
+		t.Error("downgrade should fail")
+	}
+	if _, err := Plan("1.0", "9.0"); err == nil {
+		t.Error("unknown target should fail")
+	}
+	plan, err := Plan("1.0", "1.0")
+	if err != nil || len(plan) != 0 {
+		t.Errorf("same version: plan=%d err=%v", len(plan), err)
+	}
+
+	// 目标版本可带补丁号，与 spec_version 的规整方式一致
+	short, err := Plan("1.0", "1.1")
+	if err != nil {
+		t.Fatalf("plan 1.0 -> 1.1: %v", err)
+	}
+	full, err := Plan("1.0.0", "1.1.0")
+	if err != nil {
+		t.Fatalf("plan 1.0.0 -> 1.1.0: %v", err)
+	}
+	if len(full) == 0 || len(full) != len(short) {
+		t.Errorf("patch-version target: got %d migrations, want %d", len(full), len(short))
+	}
+}
+
+func fixtures(t *testing.T, name string) (before, after []byte) {
+	t.Helper()
+	before, err := os.ReadFile(filepath.Join("testdata", name, "before.runly"))
+	if err != nil {
+		t.Fatalf("missing fixture: %v", err)
+	}
+	after, err = os.ReadFile(filepath.Join("testdata", name, "after.runly"))
+	if err != nil {
+		t.Fatalf("missing fixture: %v", err)
+	}
+	return before, after
+}
+
+func assertYAML(t *testing.T, want, got string) {
+	t.Helper()
+	if want != got {
+		t.Errorf("output mismatch:\n%s", textdiff.Unified("want", "got", want, got, 2))
+	}
+}
@@ -0,0 +1,83 @@
+package migrate
+
+import "gopkg.in/yaml.v3"
+
+// documentRoot 返回文档节点下的根节点
+func documentRoot(n *yaml.Node) *yaml.Node {
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		return n.Content[0]
+	}
+	return n
+}
+
+// mapGet 返回映射节点中指定键对应的值节点，不存在时返回 nil
+func mapGet(n *yaml.Node, key string) *yaml.Node {
+	if i := mapIndex(n, key); i >= 0 {
+		return n.Content[i+1]
+	}
+	return nil
+}
+
+// mapIndex 返回键节点在 Content 中的下标，不存在时返回 -1
+func mapIndex(n *yaml.Node, key string) int {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// mapDelete 删除映射中的键，返回被删除的值节点
+func mapDelete(n *yaml.Node, key string) *yaml.Node {
+	i := mapIndex(n, key)
+	if i < 0 {
+		return nil
+	}
+	val := n.Content[i+1]
+	n.Content = append(n.Content[:i], n.Content[i+2:]...)
+	return val
+}
+
+// mapPath 按路径逐级查找映射值，如 mapPath(root, "commerce", "pricing", "mode")
+func mapPath(n *yaml.Node, keys ...string) *yaml.Node {
+	for _, k := range keys {
+		n = mapGet(n, k)
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// items 返回序列节点的元素，非序列时返回 nil
+func items(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// walkScalars 深度优先遍历全部值标量 (映射键除外)
+func walkScalars(n *yaml.Node, fn func(*yaml.Node)) {
+	switch n.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, c := range n.Content {
+			walkScalars(c, fn)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			walkScalars(n.Content[i], fn)
+		}
+	case yaml.ScalarNode:
+		fn(n)
+	}
+}
+
+// scalar 构造字符串标量节点
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,117 @@
+package migrate
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 1.0 -> 1.1：密钥引用统一为 {{secret.provider.name}}、枚举值统一大小写、网关兜底分支显式化
+func init() {
+	Register(Migration{
+		ID:          "secret-refs",
+		From:        "1.0",
+		To:          "1.1",
+		Description: "Rewrite {{env.NAME}} as {{secret.env.NAME}}",
+		Apply:       migrateSecretRefs,
+	})
+	Register(Migration{
+		ID:          "enum-case",
+		From:        "1.0",
+		To:          "1.1",
+		Description: "Normalize enum values (node type, provider_type, pricing mode, settlement trigger, status)",
+		Apply:       migrateEnumCase,
+	})
+	Register(Migration{
+		ID:          "gate-fallback-rule",
+		From:        "1.0",
+		To:          "1.1",
+		Description: "Turn LOGIC_GATE on_success fallbacks into an explicit 'default' rule",
+		Apply:       migrateGateFallback,
+	})
+}
+
+// legacyEnvRegex 匹配旧版环境变量占位符 {{env.NAME}}
+var legacyEnvRegex = regexp.MustCompile(`\{\{\s*env\.([a-zA-Z0-9_]+)\s*\}\}`)
+
+// migrateSecretRefs 将 {{env.NAME}} 改写为等价的规范写法 {{secret.env.NAME}}
+func migrateSecretRefs(root *yaml.Node) error {
+	walkScalars(root, func(n *yaml.Node) {
+		if legacyEnvRegex.MatchString(n.Value) {
+			n.Value = legacyEnvRegex.ReplaceAllString(n.Value, "{{secret.env.$1}}")
+		}
+	})
+	return nil
+}
+
+// migrateEnumCase 枚举值统一为规范大小写：类型类枚举使用大写下划线，manifest.status 使用小写
+func migrateEnumCase(root *yaml.Node) error {
+	upper := func(n *yaml.Node) {
+		if n != nil && n.Kind == yaml.ScalarNode {
+			n.Value = strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(n.Value)))
+		}
+	}
+
+	for _, node := range items(mapPath(root, "topology", "nodes")) {
+		upper(mapGet(node, "type"))
+	}
+	for _, kb := range items(mapGet(root, "knowledge")) {
+		upper(mapGet(kb, "provider_type"))
+	}
+	upper(mapPath(root, "commerce", "pricing", "mode"))
+	upper(mapPath(root, "commerce", "settlement", "trigger"))
+
+	if status := mapPath(root, "manifest", "status"); status != nil && status.Kind == yaml.ScalarNode {
+		status.Value = strings.ToLower(strings.TrimSpace(status.Value))
+	}
+	return nil
+}
+
+// migrateGateFallback 网关未命中任何规则时回退到 on_success，1.1 起改为显式的 default 规则
+// 已存在无条件规则 (default / else / true / always) 时 on_success 永远不会生效，直接移除
+func migrateGateFallback(root *yaml.Node) error {
+	for _, node := range items(mapPath(root, "topology", "nodes")) {
+		typ := mapGet(node, "type")
+		if typ == nil || !strings.EqualFold(typ.Value, "LOGIC_GATE") {
+			continue
+		}
+		fallback := mapGet(node, "on_success")
+		if fallback == nil || fallback.Value == "" {
+			continue
+		}
+
+		rules := mapGet(node, "rules")
+		hasDefault := false
+		for _, r := range items(rules) {
+			if c := mapGet(r, "condition"); c != nil && unconditional(c.Value) {
+				hasDefault = true
+			}
+		}
+
+		if !hasDefault {
+			rule := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+				scalar("condition"), scalar("default"),
+				scalar("next"), scalar(fallback.Value),
+			}}
+			if rules == nil || rules.Kind != yaml.SequenceNode {
+				// 在 on_success 的位置插入 rules，保持字段顺序贴近原文件
+				rules = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+				i := mapIndex(node, "on_success")
+				node.Content = append(node.Content[:i], append([]*yaml.Node{scalar("rules"), rules}, node.Content[i:]...)...)
+			}
+			rules.Content = append(rules.Content, rule)
+		}
+		mapDelete(node, "on_success")
+	}
+	return nil
+}
+
+// unconditional 判断网关规则条件是否恒为真，与执行器的条件求值保持一致
+func unconditional(cond string) bool {
+	switch strings.ToLower(strings.TrimSpace(cond)) {
+	case "", "default", "else", "true", "always":
+		return true
+	}
+	return false
+}
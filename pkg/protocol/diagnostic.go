@@ -0,0 +1,166 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity 诊断的严重级别
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off" // 仅用于规则配置，表示关闭该规则
+)
+
+// Diagnostic 一条带源文件位置的协议诊断信息
+type Diagnostic struct {
+	Code       string   `json:"code,omitempty"`
+	Severity   Severity `json:"severity"`
+	Pos        Position `json:"position"`
+	Message    string   `json:"message"`
+	Suggestion string   `json:"suggestion,omitempty"`
+}
+
+// Error 实现 error 接口
+func (d *Diagnostic) Error() string {
+	return d.Message
+}
+
+// newDiagnostic 创建 error 级诊断信息，消息格式与 fmt.Sprintf 一致
+func newDiagnostic(pos Position, format string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{Severity: SeverityError, Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewRuleDiagnostic 创建归属于指定规则的诊断，级别取规则默认值
+func NewRuleDiagnostic(code string, pos Position, format string, args ...interface{}) *Diagnostic {
+	d := newDiagnostic(pos, format, args...)
+	d.Code = code
+	if rule, ok := LookupRule(code); ok {
+		d.Severity = rule.Severity
+	}
+	return d
+}
+
+// Diagnostics 一次校验收集到的全部诊断，存在 error 级诊断时可作为 error 返回
+type Diagnostics []*Diagnostic
+
+// Error 实现 error 接口，逐行列出全部诊断
+func (ds Diagnostics) Error() string {
+	msgs := make([]string, 0, len(ds))
+	for _, d := range ds {
+		msgs = append(msgs, d.Message)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// HasErrors 判断是否包含 error 级诊断
+func (ds Diagnostics) HasErrors() bool {
+	return ds.Count(SeverityError) > 0
+}
+
+// Count 统计指定级别的诊断数量
+func (ds Diagnostics) Count(sev Severity) int {
+	n := 0
+	for _, d := range ds {
+		if d.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+// Render 依次渲染全部诊断，之间以空行分隔
+func (ds Diagnostics) Render() string {
+	parts := make([]string, 0, len(ds))
+	for _, d := range ds {
+		parts = append(parts, d.Render())
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// yamlLineRegex 提取 yaml.v3 错误信息中的行号，如 "yaml: line 12: ..."
+var yamlLineRegex = regexp.MustCompile(`line (\d+):\s*`)
+
+// yamlErrorDiagnostic 将 yaml.v3 的解析错误转换为带行号的诊断
+func yamlErrorDiagnostic(file string, err error, format string) *Diagnostic {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	msg = strings.TrimPrefix(msg, "unmarshal errors:\n")
+	msg = strings.TrimSpace(strings.Split(msg, "\n")[0])
+
+	pos := Position{File: file, Line: 0, Column: 1}
+	if m := yamlLineRegex.FindStringSubmatch(msg); m != nil {
+		pos.Line, _ = strconv.Atoi(m[1])
+		msg = strings.Replace(msg, m[0], "", 1)
+	}
+	return newDiagnostic(pos, format, msg)
+}
+
+// Render 按编译器风格渲染诊断：file:line:col: severity[CODE]: message，并附带源码片段、定位标记与修复建议
+func (d *Diagnostic) Render() string {
+	var b strings.Builder
+
+	sev := string(d.Severity)
+	if sev == "" {
+		sev = string(SeverityError)
+	}
+	if d.Code != "" {
+		sev += "[" + d.Code + "]"
+	}
+
+	if d.Pos.IsValid() {
+		fmt.Fprintf(&b, "%s:%d:%d: %s: %s", d.Pos.File, d.Pos.Line, d.Pos.Column, sev, d.Message)
+	} else if d.Pos.File != "" {
+		fmt.Fprintf(&b, "%s: %s: %s", d.Pos.File, sev, d.Message)
+	} else {
+		fmt.Fprintf(&b, "%s: %s", sev, d.Message)
+	}
+
+	b.WriteString(d.snippet())
+	if d.Suggestion != "" {
+		fmt.Fprintf(&b, "\n   = help: %s", d.Suggestion)
+	}
+	return b.String()
+}
+
+// snippet 读取源文件中诊断所在行，生成带行号与 ^ 标记的片段
+func (d *Diagnostic) snippet() string {
+	if !d.Pos.IsValid() || d.Pos.File == "" {
+		return ""
+	}
+	src, err := os.ReadFile(d.Pos.File)
+	if err != nil {
+		return ""
+	}
+	line, ok := lineOf(src, d.Pos.Line)
+	if !ok {
+		return ""
+	}
+
+	gutter := strconv.Itoa(d.Pos.Line)
+	pad := strings.Repeat(" ", len(gutter))
+	col := d.Pos.Column
+	if col < 1 {
+		col = 1
+	}
+	return fmt.Sprintf("\n %s |\n %s | %s\n %s | %s^", pad, gutter, line, pad, strings.Repeat(" ", col-1))
+}
+
+// FormatError 渲染任意错误：诊断信息按编译器风格输出，其余错误原样返回
+func FormatError(err error) string {
+	var ds Diagnostics
+	if errors.As(err, &ds) {
+		return ds.Render()
+	}
+	var d *Diagnostic
+	if errors.As(err, &d) {
+		return d.Render()
+	}
+	return err.Error()
+}
@@ -0,0 +1,415 @@
+package protocol
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+// extensionPrefix 以 x- 开头的顶层字段为扩展字段：不参与校验与解码，可用于集中定义 YAML 锚点
+const extensionPrefix = "x-"
+
+// anchorPreludeKey 向片段文件注入已定义锚点时使用的临时字段，解析后即移除
+const anchorPreludeKey = "x-runly-anchors"
+
+// source 待解码的协议源：主文件原始字节、合并 include 后的语法树，以及片段节点所属的文件
+type source struct {
+	path   string
+	data   []byte
+	doc    *yaml.Node
+	files  map[*yaml.Node]string // 来自 include 片段的节点 -> 片段文件路径
+	merged bool                  // 语法树经过合并或裁剪，与 data 不再一致
+}
+
+// fileOf 返回节点所属的源文件
+func (s *source) fileOf(n *yaml.Node) string {
+	if f, ok := s.files[n]; ok {
+		return f
+	}
+	return s.path
+}
+
+// position 返回节点在其所属源文件中的位置
+func (s *source) position(n *yaml.Node) Position {
+	return Position{File: s.fileOf(n), Line: n.Line, Column: n.Column}
+}
+
+// includeSection 可由片段文件提供条目的列表字段，idKey 为条目的唯一标识字段
+type includeSection struct {
+	path  []string
+	kind  string
+	idKey string
+}
+
+var includeSections = []includeSection{
+	{[]string{"knowledge"}, "knowledge", "id"},
+	{[]string{"skills"}, "skill", "id"},
+	{[]string{"dictionary", "inputs"}, "input", "name"},
+	{[]string{"dictionary", "artifacts"}, "artifact", "id"},
+	{[]string{"topology", "nodes"}, "node", "id"},
+}
+
+// resolveIncludes 展开 includes：按声明顺序 (深度优先) 加载片段文件，将其中的条目追加到主文档对应列表，
+// 跨文件的 ID 冲突、循环引用与片段中不允许出现的字段一次性报告
+// 锚点按加载顺序可见：片段可以引用主文件及此前已加载片段中定义的锚点
+func resolveIncludes(src *source) error {
+	root := documentRoot(src.doc)
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	r := &includer{
+		src:     src,
+		root:    root,
+		anchors: collectAnchors(root, nil),
+		seen:    map[string]bool{},
+		owners:  map[string]map[string]Position{},
+	}
+	if abs, err := filepath.Abs(src.path); err == nil {
+		r.seen[abs] = true
+		r.stack = []string{abs}
+		r.base = resolvePath(filepath.Dir(abs))
+	}
+	r.register(root)
+	r.expand(root, src.path)
+
+	// 扩展字段只用于定义锚点，别名在解析阶段已展开，可直接移除
+	if stripExtensions(root) {
+		src.merged = true
+	}
+	if len(r.diags) > 0 {
+		return r.diags
+	}
+	return nil
+}
+
+// includer 展开 includes 过程中的共享状态
+type includer struct {
+	src     *source
+	root    *yaml.Node                     // 主文档根映射，片段条目合并到这里
+	anchors []*yaml.Node                   // 已加载文件中定义的锚点 (按定义顺序)
+	seen    map[string]bool                // 已加载的文件，重复引用时跳过
+	stack   []string                       // 当前 include 链，用于检测循环引用
+	owners  map[string]map[string]Position // 条目类别 -> ID -> 首次定义的位置
+	base    string                         // 主文件所在目录，片段文件不得超出该目录树
+	diags   Diagnostics
+}
+
+// expand 加载 n 中 includes 列出的片段文件 (相对于 file 所在目录，支持通配符)
+func (r *includer) expand(n *yaml.Node, file string) {
+	for _, entry := range sequenceItems(mappingValue(n, "includes")) {
+		if entry.Kind != yaml.ScalarNode {
+			continue // 类型错误由 Schema 检查报告
+		}
+		pattern := entry.Value
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(file), pattern)
+		}
+		matches, _ := filepath.Glob(pattern)
+		if len(matches) == 0 {
+			// 📂 引用的片段文件不存在
+			r.diags = append(r.diags, newDiagnostic(r.src.position(entry), i18n.T("errors.include_not_found"), entry.Value))
+			continue
+		}
+		for _, m := range matches {
+			if !r.contains(m) {
+				// 🚧 片段文件位于资产目录之外
+				r.diags = append(r.diags, newDiagnostic(r.src.position(entry), i18n.T("errors.include_outside"), entry.Value, m))
+				continue
+			}
+			r.load(m, entry)
+		}
+	}
+}
+
+// contains 判断文件 (解析符号链接后) 是否位于主文件所在目录树内，
+// 防止共享资产通过绝对路径、../ 或符号链接读取本机任意文件
+func (r *includer) contains(path string) bool {
+	if r.base == "" {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(r.base, resolvePath(abs))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// resolvePath 解析路径中的符号链接，失败时返回原路径
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// load 解析单个片段文件并合并其条目
+func (r *includer) load(path string, entry *yaml.Node) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	for i, p := range r.stack {
+		if p == abs {
+			// 🔁 循环引用
+			chain := append(append([]string{}, r.stack[i:]...), abs)
+			for j := range chain {
+				chain[j] = filepath.Base(chain[j])
+			}
+			r.diags = append(r.diags, newDiagnostic(r.src.position(entry), i18n.T("errors.include_cycle"), strings.Join(chain, " -> ")))
+			return
+		}
+	}
+	if r.seen[abs] {
+		return
+	}
+	r.seen[abs] = true
+
+	frag, d := parseFragment(path, r.anchors)
+	if d != nil {
+		r.diags = append(r.diags, d)
+		return
+	}
+	if frag == nil {
+		return // 空文件
+	}
+	markFile(frag, path, r.src)
+
+	if !r.checkFragment(frag) {
+		return
+	}
+	r.anchors = collectAnchors(frag, r.anchors)
+	r.register(frag)
+	r.merge(frag)
+	r.src.merged = true
+
+	r.stack = append(r.stack, abs)
+	r.expand(frag, path)
+	r.stack = r.stack[:len(r.stack)-1]
+}
+
+// checkFragment 片段文件只能提供列表条目 (knowledge、skills、dictionary、topology.nodes) 与扩展字段
+func (r *includer) checkFragment(frag *yaml.Node) bool {
+	ok := true
+	reject := func(key *yaml.Node, name string) {
+		// 🧩 片段文件中不允许出现该字段
+		d := newDiagnostic(r.src.position(key), i18n.T("errors.include_field_invalid"), name)
+		d.Suggestion = i18n.T("errors.hint_include_fields")
+		r.diags = append(r.diags, d)
+		ok = false
+	}
+	allowed := map[string][]string{
+		"includes":   nil,
+		"knowledge":  nil,
+		"skills":     nil,
+		"dictionary": {"inputs", "artifacts"},
+		"topology":   {"nodes"},
+	}
+	for i := 0; i+1 < len(frag.Content); i += 2 {
+		key, value := frag.Content[i], frag.Content[i+1]
+		if strings.HasPrefix(key.Value, extensionPrefix) {
+			continue
+		}
+		children, known := allowed[key.Value]
+		if !known {
+			reject(key, key.Value)
+			continue
+		}
+		if children == nil || value.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			if !contains(children, value.Content[j].Value) {
+				reject(value.Content[j], key.Value+"."+value.Content[j].Value)
+			}
+		}
+	}
+	return ok
+}
+
+// register 记录文档中各条目的 ID，与其他文件中已定义的同类条目冲突时报告
+// 同一文件内的重复定义交由静态校验 (RUN008) 处理
+func (r *includer) register(doc *yaml.Node) {
+	for _, sec := range includeSections {
+		ids := r.owners[sec.kind]
+		if ids == nil {
+			ids = map[string]Position{}
+			r.owners[sec.kind] = ids
+		}
+		for _, item := range sequenceItems(mappingPath(doc, sec.path...)) {
+			id := mappingValue(item, sec.idKey)
+			if id == nil || id.Value == "" {
+				continue
+			}
+			pos := r.src.position(id)
+			first, dup := ids[id.Value]
+			if !dup {
+				ids[id.Value] = pos
+				continue
+			}
+			if first.File != pos.File {
+				// 🪪 跨文件 ID 冲突
+				d := newDiagnostic(pos, i18n.T("errors.include_id_collision"), sec.kind, id.Value, first.File, first.Line)
+				if sec.kind == "node" {
+					d.Suggestion = i18n.T("errors.hint_unique_id")
+				}
+				r.diags = append(r.diags, d)
+			}
+		}
+	}
+}
+
+// merge 将片段中的条目追加到主文档对应列表 (列表缺失时创建)
+func (r *includer) merge(frag *yaml.Node) {
+	for _, sec := range includeSections {
+		items := sequenceItems(mappingPath(frag, sec.path...))
+		if len(items) == 0 {
+			continue
+		}
+		target := ensureSequence(r.root, sec.path...)
+		target.Content = append(target.Content, items...)
+	}
+}
+
+// parseFragment 解析片段文件；已定义的锚点以临时字段注入到文件开头，解析后移除并修正行号
+func parseFragment(path string, anchors []*yaml.Node) (*yaml.Node, *Diagnostic) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// 📂 读取片段文件失败
+		return nil, newDiagnostic(Position{File: path}, i18n.T("errors.load_fail"), err)
+	}
+
+	prelude, err := anchorPrelude(anchors)
+	if err != nil {
+		return nil, newDiagnostic(Position{File: path}, i18n.T("errors.yaml_unmarshal_fail"), err)
+	}
+	shift := bytes.Count(prelude, []byte("\n"))
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(append(prelude, data...), &doc); err != nil {
+		d := yamlErrorDiagnostic(path, err, i18n.T("errors.yaml_unmarshal_fail"))
+		if d.Pos.Line > shift {
+			d.Pos.Line -= shift
+		}
+		return nil, d
+	}
+	root := documentRoot(&doc)
+	if doc.Kind == 0 {
+		return nil, nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, newDiagnostic(Position{File: path, Line: root.Line, Column: root.Column}, i18n.T("errors.include_not_mapping"))
+	}
+	if shift > 0 && len(root.Content) >= 2 && root.Content[0].Value == anchorPreludeKey {
+		root.Content = root.Content[2:]
+		shiftLines(root, shift)
+	}
+	return root, nil
+}
+
+// anchorPrelude 将已定义的锚点编码为 "x-runly-anchors: [...]" 片段，使后续文件中的别名可以解析
+func anchorPrelude(anchors []*yaml.Node) ([]byte, error) {
+	if len(anchors) == 0 {
+		return nil, nil
+	}
+	prelude := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: anchorPreludeKey},
+		{Kind: yaml.SequenceNode, Content: anchors},
+	}}
+	return yaml.Marshal(prelude)
+}
+
+// collectAnchors 按文档顺序收集带锚点的节点 (已被外层锚点包含的不重复收集)
+func collectAnchors(n *yaml.Node, out []*yaml.Node) []*yaml.Node {
+	if n.Anchor != "" {
+		return append(out, n)
+	}
+	if n.Kind == yaml.AliasNode {
+		return out
+	}
+	for _, c := range n.Content {
+		out = collectAnchors(c, out)
+	}
+	return out
+}
+
+// markFile 记录片段语法树中全部节点所属的文件
+func markFile(n *yaml.Node, path string, src *source) {
+	if src.files == nil {
+		src.files = map[*yaml.Node]string{}
+	}
+	src.files[n] = path
+	if n.Kind == yaml.AliasNode {
+		return
+	}
+	for _, c := range n.Content {
+		markFile(c, path, src)
+	}
+}
+
+// shiftLines 扣除注入的锚点前导行，使行号与片段文件一致
+func shiftLines(n *yaml.Node, shift int) {
+	if n.Line > shift {
+		n.Line -= shift
+	}
+	if n.Kind == yaml.AliasNode {
+		return
+	}
+	for _, c := range n.Content {
+		shiftLines(c, shift)
+	}
+}
+
+// stripExtensions 移除根映射中的 x- 扩展字段，返回是否有字段被移除
+func stripExtensions(root *yaml.Node) bool {
+	kept := root.Content[:0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if strings.HasPrefix(root.Content[i].Value, extensionPrefix) {
+			continue
+		}
+		kept = append(kept, root.Content[i], root.Content[i+1])
+	}
+	stripped := len(kept) != len(root.Content)
+	root.Content = kept
+	return stripped
+}
+
+// mappingPath 按路径逐级查找映射值
+func mappingPath(n *yaml.Node, keys ...string) *yaml.Node {
+	for _, k := range keys {
+		if n = mappingValue(n, k); n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// ensureSequence 按路径查找序列节点，沿途缺失的映射与序列自动创建
+func ensureSequence(n *yaml.Node, keys ...string) *yaml.Node {
+	for i, k := range keys {
+		next := mappingValue(n, k)
+		if next == nil || next.Kind == yaml.ScalarNode && next.Tag == "!!null" {
+			kind, tag := yaml.MappingNode, "!!map"
+			if i == len(keys)-1 {
+				kind, tag = yaml.SequenceNode, "!!seq"
+			}
+			created := &yaml.Node{Kind: kind, Tag: tag}
+			if next != nil {
+				*next = *created
+			} else {
+				n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, created)
+				next = created
+			}
+		}
+		n = next
+	}
+	return n
+}
@@ -1,19 +1,33 @@
 package protocol
 
 import (
+	"bytes"
 	"fmt"
 	"os"
-	"regexp"
 
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
 	"gopkg.in/yaml.v3"
 )
 
-// envRegex 匹配占位符格式：{{env.VARIABLE_NAME}}
-var envRegex = regexp.MustCompile(`\{\{\s*env\.([a-zA-Z0-9_]+)\s*\}\}`)
+// LoadOptions 协议加载选项
+type LoadOptions struct {
+	// Lenient 宽松模式：未知字段降级为警告 (RUN013)，便于读取包含新版字段的前向兼容协议
+	Lenient bool
+}
+
+// DefaultLoadOptions Load 使用的默认选项 (严格模式)，由 --lenient 全局标志修改
+var DefaultLoadOptions LoadOptions
 
-// Load 负责从磁盘加载协议并执行动态预处理（如环境变量注入）
+// Load 使用默认选项加载协议，见 LoadWith
 func Load(path string) (*RunlyProtocol, error) {
+	return LoadWith(path, DefaultLoadOptions)
+}
+
+// LoadWith 负责从磁盘加载协议，并为各协议元素记录源文件位置
+// {{env.X}} 等密钥占位符在加载阶段保持原样，仅在执行器发送请求时解析 (见 ResolveSecrets)，
+// 以确保密钥不会进入 RunlyProtocol 对象，也不会被 build 固化或被 publish 上传
+// 严格模式下未知或拼写错误的字段 (如 on_sucess) 直接报错并给出 "did you mean" 建议，避免跳转边被静默丢弃
+func LoadWith(path string, opts LoadOptions) (*RunlyProtocol, error) {
 	// 1. 读取文件原始字节流
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -21,37 +35,78 @@ func Load(path string) (*RunlyProtocol, error) {
 		return nil, fmt.Errorf(i18n.T("errors.load_fail"), err)
 	}
 
-	// 2. 环境变量热注入 (Secret Injection)
-	// 在解析结构化对象前，先替换掉内存中的敏感信息占位符，保护密钥安全
-	processedData := injectSecrets(data)
-
-	// 3. 执行 YAML 反序列化
-	var proto RunlyProtocol
-	if err := yaml.Unmarshal(processedData, &proto); err != nil {
+	// 2. 解析为 yaml.Node 语法树，保留行列信息
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
 		// 🧩 协议语法解析异常，请检查 YAML 格式: %v
-		return nil, fmt.Errorf(i18n.T("errors.yaml_unmarshal_fail"), err)
+		return nil, yamlErrorDiagnostic(path, err, i18n.T("errors.yaml_unmarshal_fail"))
+	}
+
+	// 3. 展开 includes，将片段文件中的条目合并进主文档，并移除仅用于定义锚点的 x- 扩展字段
+	src := &source{path: path, data: data, doc: &doc}
+	if err := resolveIncludes(src); err != nil {
+		return nil, err
+	}
+
+	// 4. 按 spec_version 分派到对应版本的解码器
+	_, decode, err := detectSpecVersion(&doc, path)
+	if err != nil {
+		return nil, err
+	}
+	proto, findings, err := decode(src, opts)
+	if err != nil {
+		return nil, err
 	}
+	attachPositions(proto, src)
+	proto.Source = path
+	proto.Findings = findings
 
-	return &proto, nil
+	return proto, nil
 }
 
-// injectSecrets 查找并替换所有的环境变量占位符
-func injectSecrets(input []byte) []byte {
-	return envRegex.ReplaceAllFunc(input, func(match []byte) []byte {
-		submatch := envRegex.FindSubmatch(match)
-		if len(submatch) < 2 {
-			return match
+// decodeV1 1.0 规范解码器：解码前按 JSON Schema 一次性检查类型、枚举、必填项与未知字段
+func decodeV1(src *source, opts LoadOptions) (*RunlyProtocol, Diagnostics, error) {
+	cfg, err := LoadRuleConfig(src.path)
+	if err != nil {
+		return nil, nil, err
+	}
+	schemaDiags := checkSchema(src.doc, src)
+	if opts.Lenient {
+		for _, d := range schemaDiags {
+			if d.Code == "RUN013" {
+				d.Severity = SeverityWarning
+			}
 		}
+	}
+	findings := schemaDiags.Configure(cfg)
+	if findings.HasErrors() {
+		return nil, nil, findings
+	}
 
-		envKey := string(submatch[1])
-		// 从系统环境变量中查询真实值
-		realValue, exists := os.LookupEnv(envKey)
-
-		if !exists {
-			// 如果缺失环境变量，保留占位符，由后续的 Validator 进行语义层面的拦截提示
-			return match
+	// 未知字段未被降级或关闭时使用 KnownFields 严格解码，兜底拦截 Schema 未覆盖的字段
+	// 合并过 include 的语法树与原始字节不再一致，此时直接解码语法树 (未知字段已由 Schema 检查拦截)
+	var proto RunlyProtocol
+	if strictDecoding(opts, schemaDiags) && !src.merged {
+		dec := yaml.NewDecoder(bytes.NewReader(src.data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&proto); err != nil {
+			return nil, nil, yamlErrorDiagnostic(src.path, err, i18n.T("errors.yaml_unmarshal_fail"))
 		}
+	} else if err := src.doc.Decode(&proto); err != nil {
+		return nil, nil, yamlErrorDiagnostic(src.path, err, i18n.T("errors.yaml_unmarshal_fail"))
+	}
+	return &proto, findings, nil
+}
 
-		return []byte(realValue)
-	})
+// strictDecoding 判断是否启用 KnownFields 严格解码：宽松模式或存在被放行的未知字段时关闭
+func strictDecoding(opts LoadOptions, schemaDiags Diagnostics) bool {
+	if opts.Lenient {
+		return false
+	}
+	for _, d := range schemaDiags {
+		if d.Code == "RUN013" {
+			return false
+		}
+	}
+	return true
 }
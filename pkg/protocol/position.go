@@ -0,0 +1,157 @@
+package protocol
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Position 协议元素在源文件中的位置 (行列号从 1 开始)
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+// IsValid 判断位置信息是否可用
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// Located 记录协议元素及其字段在源文件中的位置，不参与 YAML/JSON 序列化与签名
+type Located struct {
+	Pos    Position            `yaml:"-" json:"-"`
+	Fields map[string]Position `yaml:"-" json:"-"`
+}
+
+// At 返回字段键的位置 (如 "on_success"、"config.skill_ref")，缺失时回退到元素自身位置
+func (l Located) At(field string) Position {
+	if p, ok := l.Fields[field]; ok {
+		return p
+	}
+	return l.Pos
+}
+
+// Has 判断源文件中是否声明了该字段键
+func (l Located) Has(field string) bool {
+	_, ok := l.Fields[field]
+	return ok
+}
+
+// locate 采集节点及其内部全部映射键的位置，嵌套键以点号拼接
+func locate(n *yaml.Node, file string) Located {
+	l := Located{
+		Pos:    Position{File: file, Line: n.Line, Column: n.Column},
+		Fields: make(map[string]Position),
+	}
+	collectKeys(n, "", file, l.Fields)
+	return l
+}
+
+func collectKeys(n *yaml.Node, prefix, file string, out map[string]Position) {
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+		out[path] = Position{File: file, Line: key.Line, Column: key.Column}
+		collectKeys(value, path, file, out)
+	}
+}
+
+// mappingValue 返回映射节点中指定键对应的值节点
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// documentRoot 跳过 DocumentNode 返回根映射
+func documentRoot(n *yaml.Node) *yaml.Node {
+	if n != nil && n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		return n.Content[0]
+	}
+	return n
+}
+
+// sequenceItems 返回序列节点的元素，非序列时返回空
+func sequenceItems(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// attachPositions 将 yaml.Node 中的位置信息回填到已解码的协议对象
+func attachPositions(proto *RunlyProtocol, src *source) {
+	root := documentRoot(src.doc)
+	if root == nil {
+		return
+	}
+
+	if n := mappingValue(root, "manifest"); n != nil {
+		proto.Manifest.Located = locate(n, src.fileOf(n))
+	}
+	if n := mappingValue(root, "commerce"); n != nil {
+		proto.Commerce.Located = locate(n, src.fileOf(n))
+	}
+
+	for i, item := range sequenceItems(mappingValue(root, "knowledge")) {
+		if i < len(proto.Knowledge) {
+			proto.Knowledge[i].Located = locate(item, src.fileOf(item))
+		}
+	}
+	for i, item := range sequenceItems(mappingValue(root, "skills")) {
+		if i < len(proto.Skills) {
+			proto.Skills[i].Located = locate(item, src.fileOf(item))
+		}
+	}
+
+	dict := mappingValue(root, "dictionary")
+	for i, item := range sequenceItems(mappingValue(dict, "inputs")) {
+		if i < len(proto.Dictionary.Inputs) {
+			proto.Dictionary.Inputs[i].Located = locate(item, src.fileOf(item))
+		}
+	}
+	for i, item := range sequenceItems(mappingValue(dict, "artifacts")) {
+		if i < len(proto.Dictionary.Artifacts) {
+			proto.Dictionary.Artifacts[i].Located = locate(item, src.fileOf(item))
+		}
+	}
+
+	topo := mappingValue(root, "topology")
+	if topo != nil {
+		proto.Topology.Located = locate(topo, src.fileOf(topo))
+	}
+	for i, item := range sequenceItems(mappingValue(topo, "nodes")) {
+		if i >= len(proto.Topology.Nodes) {
+			break
+		}
+		node := &proto.Topology.Nodes[i]
+		node.Located = locate(item, src.fileOf(item))
+		for j, r := range sequenceItems(mappingValue(item, "rules")) {
+			if j < len(node.Rules) {
+				node.Rules[j].Located = locate(r, src.fileOf(r))
+			}
+		}
+	}
+}
+
+// lineOf 从源文本中取出指定行 (1 起始)
+func lineOf(src []byte, line int) (string, bool) {
+	lines := strings.Split(string(src), "\n")
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+	return strings.TrimRight(lines[line-1], "\r"), true
+}
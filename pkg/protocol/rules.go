@@ -0,0 +1,255 @@
+package protocol
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule 一条静态校验规则，规则代码保持稳定，可在 .runlyrc 或内联注释中引用
+type Rule struct {
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"` // 默认级别
+	Summary  string   `json:"summary"`
+}
+
+// Rules 全部已注册规则，按代码排序
+var Rules = []Rule{
+	{"RUN001", SeverityError, "topology.start_at must reference an existing node"},
+	{"RUN002", SeverityError, "on_success, on_failure and rule targets must reference existing nodes"},
+	{"RUN003", SeverityError, "{{inputs.x}} must be declared in dictionary.inputs"},
+	{"RUN004", SeverityError, "step references must use the form {{steps.<node_id>.<field>}}"},
+	{"RUN005", SeverityError, "{{steps.x}} must reference an existing node"},
+	{"RUN006", SeverityError, "SKILL_CALL nodes must reference a declared skill"},
+	{"RUN007", SeverityError, "knowledge_ref must reference a declared knowledge resource"},
+	{"RUN008", SeverityError, "node IDs must be unique"},
+	{"RUN009", SeverityWarning, "nodes should be reachable from topology.start_at"},
+	{"RUN010", SeverityWarning, "secret placeholders should be well-formed and name a known provider"},
+	{"RUN011", SeverityWarning, "LOGIC_GATE nodes should define rules or an on_success fallback"},
+	{"RUN012", SeverityError, "enumerated fields (node type, provider_type, pricing mode, settlement trigger) must use an allowed value"},
+	{"RUN013", SeverityError, "unknown or misspelled fields are rejected (a warning with --lenient)"},
+	{"RUN014", SeverityError, "field values must match the type defined by the protocol schema"},
+	{"RUN015", SeverityError, "required fields must be present"},
+	{"RUN016", SeverityError, "manifest.version and manifest.min_runtime must be valid semantic versions (MAJOR.MINOR.PATCH)"},
+}
+
+// RegisterRules 注册扩展规则 (如 lint 规则)，使其可被 .runlyrc 与内联注释引用
+func RegisterRules(rules ...Rule) {
+	Rules = append(Rules, rules...)
+	sort.Slice(Rules, func(i, j int) bool { return Rules[i].Code < Rules[j].Code })
+}
+
+// LookupRule 按代码查找规则
+func LookupRule(code string) (Rule, bool) {
+	for _, r := range Rules {
+		if r.Code == code {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// RCFileName 规则配置文件名
+const RCFileName = ".runlyrc"
+
+// RuleConfig 规则配置：可将指定规则关闭 (off) 或调整为 error / warning / info
+//
+//	rules:
+//	  RUN009: off
+//	  RUN010: error
+//	signatures:        # check 的签名策略：各角色至少需要的可信签名者数量
+//	  creator: 1
+//	  reviewer: 1
+type RuleConfig struct {
+	Rules      map[string]Severity `yaml:"rules" json:"rules"`
+	Signatures map[string]int      `yaml:"signatures,omitempty" json:"signatures,omitempty"`
+}
+
+// LoadRuleConfig 从协议文件所在目录逐级向上查找 .runlyrc，未找到时返回空配置
+func LoadRuleConfig(protoPath string) (*RuleConfig, error) {
+	cfg := &RuleConfig{Rules: make(map[string]Severity)}
+	if protoPath == "" {
+		return cfg, nil
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(protoPath))
+	if err != nil {
+		return cfg, nil
+	}
+	for {
+		path := filepath.Join(dir, RCFileName)
+		if data, err := os.ReadFile(path); err == nil {
+			if err := yaml.Unmarshal(data, cfg); err != nil {
+				// ⚙️ 规则配置文件无效
+				return nil, fmt.Errorf(i18n.T("errors.runlyrc_invalid"), path, err)
+			}
+			if err := cfg.validate(); err != nil {
+				return nil, fmt.Errorf(i18n.T("errors.runlyrc_invalid"), path, err)
+			}
+			return cfg, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return cfg, nil
+		}
+		dir = parent
+	}
+}
+
+// validate 检查配置中的规则代码、级别与签名策略是否合法
+func (c *RuleConfig) validate() error {
+	for code, sev := range c.Rules {
+		if _, ok := LookupRule(code); !ok {
+			return fmt.Errorf(i18n.T("errors.rule_unknown"), code)
+		}
+		if !validSeverity(sev) {
+			return fmt.Errorf(i18n.T("errors.severity_invalid"), code, sev)
+		}
+	}
+	for role, n := range c.Signatures {
+		if !ValidRole(role) || n < 1 {
+			return fmt.Errorf(i18n.T("errors.signature_policy_invalid"), fmt.Sprintf("%s=%d", role, n))
+		}
+	}
+	return nil
+}
+
+func validSeverity(s Severity) bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return true
+	}
+	return false
+}
+
+// directiveRegex 匹配内联注释指令：
+//
+//	# runly-disable RUN009            关闭本行的指定规则 (省略代码则关闭全部)
+//	# runly-disable-next-line RUN009  关闭下一行的指定规则
+//	# runly-disable-file RUN009       关闭整个文件的指定规则
+//	# runly-rule RUN010=error         调整整个文件中指定规则的级别
+var directiveRegex = regexp.MustCompile(`#\s*runly-(disable-next-line|disable-file|disable|rule)\b([^#]*)`)
+
+// inlineDirectives 源文件中的内联规则指令
+type inlineDirectives struct {
+	lines    map[int]map[string]bool // 行号 -> 被关闭的规则 ("*" 表示全部)
+	file     map[string]bool         // 整个文件被关闭的规则
+	severity map[string]Severity     // 整个文件的级别调整
+}
+
+// parseDirectives 扫描源文件中的内联注释指令
+func parseDirectives(src []byte) *inlineDirectives {
+	d := &inlineDirectives{
+		lines:    make(map[int]map[string]bool),
+		file:     make(map[string]bool),
+		severity: make(map[string]Severity),
+	}
+
+	for i, line := range strings.Split(string(src), "\n") {
+		m := directiveRegex.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		lineNo := i + 1
+		args := strings.FieldsFunc(m[2], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' || r == '\r' })
+
+		switch m[1] {
+		case "rule":
+			for _, arg := range args {
+				code, sev, ok := strings.Cut(arg, "=")
+				if ok && validSeverity(Severity(sev)) {
+					d.severity[code] = Severity(sev)
+				}
+			}
+		case "disable-file":
+			d.disable(d.file, args)
+		case "disable", "disable-next-line":
+			target := lineNo
+			if m[1] == "disable-next-line" {
+				target++
+			}
+			if d.lines[target] == nil {
+				d.lines[target] = make(map[string]bool)
+			}
+			d.disable(d.lines[target], args)
+		}
+	}
+	return d
+}
+
+// disable 将规则代码加入关闭集合，未指定代码时关闭全部规则
+func (d *inlineDirectives) disable(set map[string]bool, codes []string) {
+	if len(codes) == 0 {
+		set["*"] = true
+		return
+	}
+	for _, c := range codes {
+		set[c] = true
+	}
+}
+
+// suppressed 判断诊断是否被内联注释关闭
+func (d *inlineDirectives) suppressed(diag *Diagnostic) bool {
+	if d.file["*"] || d.file[diag.Code] {
+		return true
+	}
+	set := d.lines[diag.Pos.Line]
+	return set["*"] || set[diag.Code]
+}
+
+// Configure 按规则配置与源文件内联注释调整诊断级别，移除被关闭的诊断，并按源码位置排序
+// 优先级：内联注释 > .runlyrc > 规则默认级别
+func (ds Diagnostics) Configure(cfg *RuleConfig) Diagnostics {
+	directives := make(map[string]*inlineDirectives)
+	directivesOf := func(file string) *inlineDirectives {
+		if d, ok := directives[file]; ok {
+			return d
+		}
+		src, _ := os.ReadFile(file)
+		d := parseDirectives(src)
+		directives[file] = d
+		return d
+	}
+
+	out := make(Diagnostics, 0, len(ds))
+	for _, diag := range ds {
+		if diag.Code != "" {
+			if cfg != nil {
+				if sev, ok := cfg.Rules[diag.Code]; ok {
+					diag.Severity = sev
+				}
+			}
+			if diag.Pos.File != "" {
+				inline := directivesOf(diag.Pos.File)
+				if sev, ok := inline.severity[diag.Code]; ok {
+					diag.Severity = sev
+				}
+				if inline.suppressed(diag) {
+					continue
+				}
+			}
+		}
+		if diag.Severity == SeverityOff {
+			continue
+		}
+		out = append(out, diag)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i].Pos, out[j].Pos
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return out
+}
@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SpecVersion 当前 CLI 实现的最新协议规范版本，新建与迁移后的资产使用该版本
+const SpecVersion = "1.1"
+
+// legacySpecVersion 未声明 spec_version 的协议 (引入该字段之前) 所属的规范版本
+const legacySpecVersion = "1.0"
+
+// schemaDialect JSON Schema 草案版本
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema JSON Schema (draft 2020-12) 的子集，足以描述 RunlyProtocol
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"` // false 或 *Schema
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+
+	propOrder []string // 字段声明顺序，用于稳定输出诊断
+}
+
+// GenerateSchema 基于 types.go 中的结构体定义反射生成当前规范版本 (SpecVersion) 的 JSON Schema
+// 已支持的规范版本共用同一结构 (见 version.go)，出现结构不同的版本时再按版本生成
+func GenerateSchema() *Schema {
+	b := &schemaBuilder{defs: make(map[string]*Schema)}
+	root := b.object(reflect.TypeOf(RunlyProtocol{}))
+	root.Schema = schemaDialect
+	root.ID = "https://runlyhub.com/schema/runly-" + SpecVersion + ".json"
+	root.Title = "Runly Protocol " + SpecVersion
+	root.Defs = b.defs
+	return root
+}
+
+// schemaBuilder 递归构建 Schema，具名结构体统一收录到 $defs 并以 $ref 引用
+type schemaBuilder struct {
+	defs map[string]*Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// build 将 Go 类型映射为 Schema
+func (b *schemaBuilder) build(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.build(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.build(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.build(t.Elem())}
+	case reflect.Struct:
+		if _, ok := b.defs[t.Name()]; !ok {
+			b.defs[t.Name()] = nil // 占位，防止自引用类型无限递归
+			b.defs[t.Name()] = b.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	default:
+		// interface{} 等任意值
+		return &Schema{}
+	}
+}
+
+// object 按 yaml 标签展开结构体字段，yaml:"-" 字段 (如位置信息) 不出现在 Schema 中
+func (b *schemaBuilder) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		prop := b.build(f.Type)
+		for _, opt := range strings.Split(f.Tag.Get("jsonschema"), ",") {
+			switch {
+			case opt == "required":
+				s.Required = append(s.Required, name)
+			case strings.HasPrefix(opt, "enum="):
+				prop.Enum = strings.Split(strings.TrimPrefix(opt, "enum="), "|")
+			}
+		}
+		s.Properties[name] = prop
+		s.propOrder = append(s.propOrder, name)
+	}
+	return s
+}
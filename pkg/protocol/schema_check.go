@@ -0,0 +1,204 @@
+package protocol
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	specSchemaOnce sync.Once
+	specSchema     *Schema
+)
+
+// currentSchema 返回当前规范版本的 Schema (进程内只生成一次)
+func currentSchema() *Schema {
+	specSchemaOnce.Do(func() {
+		specSchema = GenerateSchema()
+	})
+	return specSchema
+}
+
+// CheckSchema 在解码前按 JSON Schema 校验 YAML 语法树，一次性收集类型错误、枚举越界、必填项缺失与未知字段
+func CheckSchema(doc *yaml.Node, file string) Diagnostics {
+	return checkSchema(doc, &source{path: file})
+}
+
+// checkSchema 同 CheckSchema，诊断定位到各节点所属的源文件 (含 include 片段)
+func checkSchema(doc *yaml.Node, src *source) Diagnostics {
+	root := documentRoot(doc)
+	if root == nil {
+		return nil
+	}
+	s := currentSchema()
+	c := &schemaChecker{defs: s.Defs, src: src}
+	c.check(root, s, "")
+	return c.diags
+}
+
+// schemaChecker 基于 yaml.Node 的轻量 Schema 校验器，仅支持 GenerateSchema 产出的关键字
+type schemaChecker struct {
+	defs  map[string]*Schema
+	src   *source
+	diags Diagnostics
+}
+
+// report 记录一条定位到 YAML 节点的诊断
+func (c *schemaChecker) report(code string, n *yaml.Node, key string, args ...interface{}) *Diagnostic {
+	d := NewRuleDiagnostic(code, c.src.position(n), i18n.T(key), args...)
+	c.diags = append(c.diags, d)
+	return d
+}
+
+// resolve 展开 $ref 引用
+func (c *schemaChecker) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = c.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+	}
+	return s
+}
+
+// check 递归校验节点，path 为点号分隔的字段路径 (用于提示)
+func (c *schemaChecker) check(n *yaml.Node, s *Schema, path string) {
+	s = c.resolve(s)
+	if s == nil || n == nil {
+		return
+	}
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	// 显式 null 等价于零值，与解码行为保持一致
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+
+	if s.Type != "" && !typeMatches(n, s.Type) {
+		// 🧬 字段 %s 类型错误：应为 %s，实际为 %s
+		c.report("RUN014", n, "errors.schema_type_mismatch", displayPath(path), s.Type, yamlKind(n))
+		return
+	}
+
+	if len(s.Enum) > 0 && !contains(s.Enum, n.Value) {
+		// 🏷️ 字段 %s 的取值 [%s] 不在允许范围内: %s
+		c.report("RUN012", n, "errors.schema_enum_mismatch", displayPath(path), n.Value, strings.Join(s.Enum, " | ")).
+			Suggestion = didYouMean(n.Value, s.Enum, "errors.hint_enum")
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		c.checkObject(n, s, path)
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			c.check(item, s.Items, joinPath(path, "["+strconv.Itoa(i)+"]"))
+		}
+	}
+}
+
+// checkObject 校验映射节点的字段：已知字段递归校验，未知字段与缺失的必填字段记录诊断
+func (c *schemaChecker) checkObject(n *yaml.Node, s *Schema, path string) {
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keyNode, valNode := n.Content[i], n.Content[i+1]
+		key := keyNode.Value
+		if key == "<<" {
+			continue
+		}
+		seen[key] = true
+
+		if prop, ok := s.Properties[key]; ok {
+			c.check(valNode, prop, joinPath(path, key))
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case *Schema:
+			c.check(valNode, extra, joinPath(path, key))
+		case bool:
+			if !extra {
+				// ❓ 未知字段 %s
+				c.report("RUN013", keyNode, "errors.schema_unknown_field", joinPath(path, key)).
+					Suggestion = didYouMean(key, s.fieldNames(), "errors.hint_unknown_field")
+			}
+		}
+	}
+
+	for _, req := range s.Required {
+		if !seen[req] {
+			// 📋 缺少必填字段 %s
+			c.report("RUN015", n, "errors.schema_required_missing", joinPath(path, req))
+		}
+	}
+}
+
+// fieldNames 返回对象 Schema 声明的字段名 (按声明顺序)
+func (s *Schema) fieldNames() []string {
+	if len(s.propOrder) > 0 {
+		return s.propOrder
+	}
+	names := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// typeMatches 判断 YAML 节点能否解码为 Schema 类型，规则与 yaml.v3 解码到 Go 类型时一致
+func typeMatches(n *yaml.Node, typ string) bool {
+	switch typ {
+	case "object":
+		return n.Kind == yaml.MappingNode
+	case "array":
+		return n.Kind == yaml.SequenceNode
+	case "string":
+		// 任意标量均可解码为字符串 (如 version: 1.0)
+		return n.Kind == yaml.ScalarNode
+	case "integer":
+		return n.Kind == yaml.ScalarNode && n.Tag == "!!int"
+	case "number":
+		return n.Kind == yaml.ScalarNode && (n.Tag == "!!int" || n.Tag == "!!float")
+	case "boolean":
+		return n.Kind == yaml.ScalarNode && n.Tag == "!!bool"
+	}
+	return true
+}
+
+// yamlKind 描述 YAML 节点的实际类型
+func yamlKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch n.Tag {
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	case "!!bool":
+		return "boolean"
+	case "!!str":
+		return "string"
+	}
+	return strings.TrimPrefix(n.Tag, "!!")
+}
+
+// joinPath 拼接字段路径，数组下标直接追加
+func joinPath(path, key string) string {
+	if path == "" || strings.HasPrefix(key, "[") {
+		return path + key
+	}
+	return path + "." + key
+}
+
+// displayPath 根路径显示为 "."
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,201 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/secrets"
+)
+
+// secretRegex 匹配密钥占位符：{{env.NAME}} (等价于 {{secret.env.NAME}}) 或 {{secret.provider.name}}，
+// name 的语法见 secrets.NamePattern
+var secretRegex = regexp.MustCompile(`\{\{\s*(?:env\.([a-zA-Z0-9_]+)|secret\.([a-zA-Z0-9_]+)\.(` + secrets.NamePattern + `))\s*\}\}`)
+
+// secretLikeRegex 匹配形似密钥占位符的文本，不符合 secretRegex 的即为写法错误的引用
+var secretLikeRegex = regexp.MustCompile(`\{\{\s*(?:secret|env)\.[^{}]*\}\}`)
+
+// authSchemeRegex 凭证请求头中允许出现在密钥占位符之外的认证方案前缀 (如 Bearer、Basic)
+var authSchemeRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z\-]{0,15}$`)
+
+// passiveProviders 泄露检测只读取这些后端：读取时不执行外部命令、不提示输入口令
+var passiveProviders = map[string]bool{"env": true, "dotenv": true}
+
+// minLeakLength 参与泄露检测的密钥最短长度，过短的值 (如 "1") 会在任意文本中误报
+const minLeakLength = 4
+
+// SecretRef 一个密钥引用
+type SecretRef struct {
+	Provider string `json:"provider"`
+	Name     string `json:"name"`
+}
+
+// String 返回引用的规范写法 secret.provider.name
+func (r SecretRef) String() string {
+	return "secret." + r.Provider + "." + r.Name
+}
+
+// parseSecretRef 从正则子匹配中还原引用
+func parseSecretRef(m []string) SecretRef {
+	if m[1] != "" {
+		return SecretRef{Provider: "env", Name: m[1]}
+	}
+	return SecretRef{Provider: m[2], Name: m[3]}
+}
+
+// SensitiveHeaderHints 命中这些关键字的请求头被视为凭证：展示时脱敏，lint 要求以密钥占位符引用
+var SensitiveHeaderHints = []string{"authorization", "token", "key", "secret", "cookie", "password"}
+
+// IsSensitiveHeader 判断请求头是否承载凭证
+func IsSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, hint := range SensitiveHeaderHints {
+		if strings.Contains(lower, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// StripSecretRefs 移除字符串中的全部密钥占位符，用于检查占位符以外是否还夹带了明文内容
+func StripSecretRefs(s string) string {
+	return secretRegex.ReplaceAllString(s, "")
+}
+
+// IsSecretOnly 凭证值必须引用密钥，且占位符之外最多只有一个认证方案前缀
+func IsSecretOnly(value string) bool {
+	if !HasSecretRef(value) {
+		return false
+	}
+	rest := strings.Fields(StripSecretRefs(value))
+	return len(rest) == 0 || (len(rest) == 1 && authSchemeRegex.MatchString(rest[0]))
+}
+
+// HardcodedCredentials 返回值未通过密钥占位符引用的凭证请求头 (资源 ID/请求头，排序)，
+// 与 LINT005 同一判定；build 与 publish 据此拒绝携带明文凭证的资产
+func HardcodedCredentials(proto *RunlyProtocol) []string {
+	var found []string
+	check := func(id string, headers map[string]string) {
+		for name, value := range headers {
+			if IsSensitiveHeader(name) && value != "" && !IsSecretOnly(value) {
+				found = append(found, id+"/"+name)
+			}
+		}
+	}
+	for _, s := range proto.Skills {
+		check(s.ID, s.Config.Headers)
+	}
+	for _, k := range proto.Knowledge {
+		check(k.ID, k.Config.Headers)
+	}
+	sort.Strings(found)
+	return found
+}
+
+// HasSecretRef 判断字符串中是否包含密钥占位符
+func HasSecretRef(s string) bool {
+	return secretRegex.MatchString(s)
+}
+
+// MalformedSecretRefs 返回协议中写法错误的密钥占位符 (去重、排序)，只检查语法，不读取任何密钥
+func MalformedSecretRefs(proto *RunlyProtocol) []string {
+	data, _ := json.Marshal(proto)
+
+	seen := make(map[string]bool)
+	var bad []string
+	for _, m := range secretLikeRegex.FindAllString(string(data), -1) {
+		if !secretRegex.MatchString(m) && !seen[m] {
+			seen[m] = true
+			bad = append(bad, m)
+		}
+	}
+	sort.Strings(bad)
+	return bad
+}
+
+// SecretRefs 返回协议中引用的全部密钥 (去重、排序)
+func SecretRefs(proto *RunlyProtocol) []SecretRef {
+	data, _ := json.Marshal(proto)
+
+	seen := make(map[SecretRef]bool)
+	var refs []SecretRef
+	for _, m := range secretRegex.FindAllStringSubmatch(string(data), -1) {
+		ref := parseSecretRef(m)
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].String() < refs[j].String() })
+	return refs
+}
+
+// ResolveSecrets 将字符串中的密钥占位符替换为真实值
+// 仅应在执行器发送请求前调用，解析结果不得回写到协议对象中
+func ResolveSecrets(s string) (string, error) {
+	var firstErr error
+	resolved := secretRegex.ReplaceAllStringFunc(s, func(match string) string {
+		ref := parseSecretRef(secretRegex.FindStringSubmatch(match))
+		value, err := secrets.Default.Resolve(ref.Provider, ref.Name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return match
+		}
+		return value
+	})
+	return resolved, firstErr
+}
+
+// UnresolvedSecrets 返回当前环境下无法解析的密钥引用及原因
+// 会读取全部后端 (执行外部命令、解锁保险库)，仅应在 run 执行前调用
+func UnresolvedSecrets(proto *RunlyProtocol) map[SecretRef]error {
+	failed := make(map[SecretRef]error)
+	for _, ref := range SecretRefs(proto) {
+		if _, err := secrets.Default.Lookup(ref.Provider, ref.Name); err != nil {
+			failed[ref] = err
+		}
+	}
+	return failed
+}
+
+// DetectSecretLeaks 检查序列化后的资产中是否出现了被引用密钥的真实值，返回泄露的引用；
+// 只检查 env 与 dotenv 引用，vault 与 cmd 后端的读取带有副作用 (解锁保险库、执行外部命令)
+func DetectSecretLeaks(serialized []byte, proto *RunlyProtocol) []string {
+	content := string(serialized)
+
+	var leaked []string
+	for _, ref := range SecretRefs(proto) {
+		if !passiveProviders[ref.Provider] {
+			continue
+		}
+		value, err := secrets.Default.Resolve(ref.Provider, ref.Name)
+		if err != nil || len(value) < minLeakLength {
+			continue
+		}
+		if strings.Contains(content, value) {
+			leaked = append(leaked, ref.String())
+		}
+	}
+	return leaked
+}
+
+// ValidateSecrets 一次性列出全部无法解析的密钥，避免执行到一半才失败
+func ValidateSecrets(proto *RunlyProtocol) error {
+	failed := UnresolvedSecrets(proto)
+	if len(failed) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(failed))
+	for ref, err := range failed {
+		lines = append(lines, fmt.Sprintf("  - {{%s}}: %v", ref, err))
+	}
+	sort.Strings(lines)
+	// 🔐 以下密钥在当前环境中无法解析:
+	return fmt.Errorf("%s\n%s", i18n.T("errors.secrets_unresolved_list"), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,55 @@
+package protocol
+
+import "strings"
+
+// Closest 返回候选项中与 target 编辑距离最近的一项，差异过大时返回空字符串
+// 用于 "did you mean" 类修复建议
+func Closest(target string, candidates []string) string {
+	if target == "" {
+		return ""
+	}
+
+	// 允许的最大编辑距离：短标识符至少容忍 2 处差异，长标识符按 1/3 长度放宽
+	limit := len(target) / 3
+	if limit < 2 {
+		limit = 2
+	}
+
+	best, bestDist := "", limit+1
+	for _, c := range candidates {
+		if c == target {
+			continue
+		}
+		// 仅大小写不同 (如枚举值 instant / INSTANT) 时直接命中
+		if strings.EqualFold(c, target) {
+			return c
+		}
+		if d := editDistance(target, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance 计算两个字符串的 Levenshtein 编辑距离
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
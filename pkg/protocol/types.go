@@ -4,25 +4,32 @@ import "time"
 
 // RunlyProtocol 代表整个 .runly 文件的根结构，是 Runly 资产的终极载体
 type RunlyProtocol struct {
-	Manifest   Manifest            `yaml:"manifest" json:"manifest"`
-	Knowledge  []KnowledgeResource `yaml:"knowledge,omitempty" json:"knowledge,omitempty"`
-	Skills     []SkillResource     `yaml:"skills,omitempty" json:"skills,omitempty"`
-	Dictionary Dictionary          `yaml:"dictionary" json:"dictionary"`
-	Topology   Topology            `yaml:"topology" json:"topology"`
-	Commerce   Commerce            `yaml:"commerce" json:"commerce"`
-	Security   Security            `yaml:"security" json:"security"`
+	SpecVersion string              `yaml:"spec_version,omitempty" json:"spec_version,omitempty"` // 协议规范版本，缺省为 1.0
+	Includes    []string            `yaml:"includes,omitempty" json:"includes,omitempty"`         // 片段文件 (相对路径，支持通配符)，加载时合并，build 时内联
+	Manifest    Manifest            `yaml:"manifest" json:"manifest" jsonschema:"required"`
+	Knowledge   []KnowledgeResource `yaml:"knowledge,omitempty" json:"knowledge,omitempty"`
+	Skills      []SkillResource     `yaml:"skills,omitempty" json:"skills,omitempty"`
+	Dictionary  Dictionary          `yaml:"dictionary" json:"dictionary"`
+	Topology    Topology            `yaml:"topology" json:"topology" jsonschema:"required"`
+	Commerce    Commerce            `yaml:"commerce" json:"commerce"`
+	Security    Security            `yaml:"security" json:"security"`
+
+	Source   string      `yaml:"-" json:"-"` // 加载来源文件路径，不参与序列化
+	Findings Diagnostics `yaml:"-" json:"-"` // 加载阶段产生的非阻断诊断 (如未知字段)
 }
 
 // 1. MANIFEST - 协议元数据，定义资产的身份与版本
 type Manifest struct {
-	URN        string    `yaml:"urn" json:"urn"`                 // 资源统一名称
-	Title      string    `yaml:"title" json:"title"`             // 资产标题
-	Version    string    `yaml:"version" json:"version"`         // 语义化版本
-	Status     string    `yaml:"status" json:"status"`           // 状态: draft | published | deprecated
-	Creator    Creator   `yaml:"creator" json:"creator"`         // 创作者信息
-	CreatedAt  time.Time `yaml:"created_at" json:"created_at"`   // 创建时间
-	UpdatedAt  time.Time `yaml:"updated_at" json:"updated_at"`   // 更新时间
-	MinRuntime string    `yaml:"min_runtime" json:"min_runtime"` // 最低 CLI 版本要求
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	URN        string    `yaml:"urn" json:"urn" jsonschema:"required"`         // 资源统一名称
+	Title      string    `yaml:"title" json:"title" jsonschema:"required"`     // 资产标题
+	Version    string    `yaml:"version" json:"version" jsonschema:"required"` // 语义化版本
+	Status     string    `yaml:"status" json:"status"`                         // 状态: draft | published | deprecated
+	Creator    Creator   `yaml:"creator" json:"creator"`                       // 创作者信息
+	CreatedAt  time.Time `yaml:"created_at" json:"created_at"`                 // 创建时间
+	UpdatedAt  time.Time `yaml:"updated_at" json:"updated_at"`                 // 更新时间
+	MinRuntime string    `yaml:"min_runtime" json:"min_runtime"`               // 最低 CLI 版本要求
 }
 
 type Creator struct {
@@ -33,8 +40,10 @@ type Creator struct {
 
 // 2. KNOWLEDGE - 知识资源契约
 type KnowledgeResource struct {
-	ID           string             `yaml:"id" json:"id"`
-	ProviderType string             `yaml:"provider_type" json:"provider_type"` // SEMANTIC_API | VDB_DIRECT
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	ID           string             `yaml:"id" json:"id" jsonschema:"required"`
+	ProviderType string             `yaml:"provider_type" json:"provider_type" jsonschema:"enum=SEMANTIC_API|VDB_DIRECT"` // 知识接入方式
 	Description  string             `yaml:"description" json:"description"`
 	Config       KnowledgeConfig    `yaml:"config" json:"config"`
 	Injection    KnowledgeInjection `yaml:"injection" json:"injection"`
@@ -65,7 +74,9 @@ type KnowledgeInjection struct {
 
 // 3. SKILLS - 技能/工具能力契约
 type SkillResource struct {
-	ID          string        `yaml:"id" json:"id"`
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	ID          string        `yaml:"id" json:"id" jsonschema:"required"`
 	Type        string        `yaml:"type" json:"type"`
 	Description string        `yaml:"description" json:"description"`
 	Config      SkillConfig   `yaml:"config" json:"config"`
@@ -73,11 +84,13 @@ type SkillResource struct {
 }
 
 type SkillConfig struct {
-	Endpoint   string            `yaml:"endpoint" json:"endpoint"`
-	Method     string            `yaml:"method" json:"method"`
-	Timeout    int               `yaml:"timeout" json:"timeout"`
-	MaxRetries int               `yaml:"max_retries" json:"max_retries"`
-	Headers    map[string]string `yaml:"headers" json:"headers"`
+	Endpoint    string            `yaml:"endpoint" json:"endpoint"`
+	Method      string            `yaml:"method" json:"method"`
+	Timeout     int               `yaml:"timeout" json:"timeout"`
+	MaxRetries  int               `yaml:"max_retries" json:"max_retries"`
+	Headers     map[string]string `yaml:"headers" json:"headers"`
+	CostPerCall float64           `yaml:"cost_per_call,omitempty" json:"cost_per_call,omitempty"` // 单次调用成本，用于运行成本核算
+	CacheTTL    int               `yaml:"cache_ttl,omitempty" json:"cache_ttl,omitempty"`         // 响应缓存有效期 (秒)，0 表示不缓存
 }
 
 type SkillContract struct {
@@ -97,7 +110,9 @@ type Dictionary struct {
 }
 
 type Parameter struct {
-	Name     string      `yaml:"name" json:"name"`
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	Name     string      `yaml:"name" json:"name" jsonschema:"required"`
 	Type     string      `yaml:"type" json:"type"`
 	Pattern  string      `yaml:"pattern,omitempty" json:"pattern,omitempty"`
 	Values   []string    `yaml:"values,omitempty" json:"values,omitempty"`
@@ -106,7 +121,9 @@ type Parameter struct {
 }
 
 type Artifact struct {
-	ID          string                 `yaml:"id" json:"id"`
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	ID          string                 `yaml:"id" json:"id" jsonschema:"required"`
 	Type        string                 `yaml:"type" json:"type"`
 	Description string                 `yaml:"description" json:"description"`
 	Schema      map[string]interface{} `yaml:"schema" json:"schema"`
@@ -114,13 +131,17 @@ type Artifact struct {
 
 // 5. TOPOLOGY - 任务调度拓扑图
 type Topology struct {
-	StartAt string `yaml:"start_at" json:"start_at"`
-	Nodes   []Node `yaml:"nodes" json:"nodes"`
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	StartAt string `yaml:"start_at" json:"start_at" jsonschema:"required"`
+	Nodes   []Node `yaml:"nodes" json:"nodes" jsonschema:"required"`
 }
 
 type Node struct {
-	ID        string                 `yaml:"id" json:"id"`
-	Type      string                 `yaml:"type" json:"type"` // SKILL_CALL | AI_TASK | HITL | LOGIC_GATE | TERMINUS
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	ID        string                 `yaml:"id" json:"id" jsonschema:"required"`
+	Type      string                 `yaml:"type" json:"type" jsonschema:"required,enum=SKILL_CALL|AI_TASK|HITL|LOGIC_GATE|TERMINUS"` // 节点类型
 	Config    map[string]interface{} `yaml:"config" json:"config"`
 	OnSuccess string                 `yaml:"on_success,omitempty" json:"on_success,omitempty"`
 	OnFailure string                 `yaml:"on_failure,omitempty" json:"on_failure,omitempty"`
@@ -128,19 +149,23 @@ type Node struct {
 }
 
 type LogicRule struct {
-	Condition string `yaml:"condition" json:"condition"`
-	Next      string `yaml:"next" json:"next"`
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
+	Condition string `yaml:"condition" json:"condition" jsonschema:"required"`
+	Next      string `yaml:"next" json:"next" jsonschema:"required"`
 }
 
 // 6. COMMERCE - 商业授权与清算模块
 type Commerce struct {
+	Located `yaml:"-" json:"-"` // 源文件位置，不参与序列化
+
 	Pricing    Pricing    `yaml:"pricing" json:"pricing"`
 	Royalty    Royalty    `yaml:"royalty" json:"royalty"`
 	Settlement Settlement `yaml:"settlement" json:"settlement"`
 }
 
 type Pricing struct {
-	Mode     string  `yaml:"mode" json:"mode"` // FREE | PAY_PER_USE | SUBSCRIPTION
+	Mode     string  `yaml:"mode" json:"mode" jsonschema:"enum=FREE|PAY_PER_USE|SUBSCRIPTION"` // 计费模式
 	Amount   float64 `yaml:"amount" json:"amount"`
 	Currency string  `yaml:"currency" json:"currency"`
 }
@@ -151,11 +176,35 @@ type Royalty struct {
 }
 
 type Settlement struct {
-	Trigger string `yaml:"trigger" json:"trigger"` // INSTANT | BATCH_MONTHLY
+	Trigger string `yaml:"trigger" json:"trigger" jsonschema:"enum=INSTANT|BATCH_MONTHLY"` // 清算触发方式
 }
 
 // 7. SECURITY - 资产指纹与数字签名
 type Security struct {
-	HashAlgo  string `yaml:"hash_algo" json:"hash_algo"` // SHA-256
-	Signature string `yaml:"signature" json:"signature"` // Ed25519 Signature (Hex)
+	HashAlgo         string      `yaml:"hash_algo" json:"hash_algo"`                                                         // SHA-256
+	Canonicalization string      `yaml:"canonicalization,omitempty" json:"canonicalization,omitempty" jsonschema:"enum=JCS"` // 签名规范化方案：JCS (RFC 8785)，缺省为旧版 json.Marshal
+	Signature        string      `yaml:"signature" json:"signature"`                                                         // Ed25519 Signature (Hex)，创建者签名
+	Signatures       []Signature `yaml:"signatures,omitempty" json:"signatures,omitempty"`                                   // 多方签名：创建者、审核人与组织联署
+}
+
+// 签名角色
+const (
+	RoleCreator      = "creator"
+	RoleReviewer     = "reviewer"
+	RoleOrganization = "organization"
+)
+
+// ValidRole 判断签名角色是否合法
+func ValidRole(role string) bool {
+	return role == RoleCreator || role == RoleReviewer || role == RoleOrganization
+}
+
+// Signature 一方对资产内容摘要的签名，签名对象见 compiler.SignaturePayload
+type Signature struct {
+	SignerID  string    `yaml:"signer_id" json:"signer_id" jsonschema:"required"`                          // 签名者 MeID
+	KeyID     string    `yaml:"key_id" json:"key_id" jsonschema:"required"`                                // 签名公钥 (Hex)
+	Algorithm string    `yaml:"algorithm" json:"algorithm" jsonschema:"enum=Ed25519"`                      // 签名算法
+	Role      string    `yaml:"role" json:"role" jsonschema:"required,enum=creator|reviewer|organization"` // 签名角色
+	SignedAt  time.Time `yaml:"signed_at" json:"signed_at"`                                                // 签名时间
+	Value     string    `yaml:"value" json:"value" jsonschema:"required"`                                  // Ed25519 Signature (Hex)
 }
@@ -2,118 +2,281 @@ package protocol
 
 import (
 	"fmt"
+	"os"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/secrets"
+	"github.com/originbeat-inc/runly-cli/pkg/semver"
 )
 
 // varExtractRegex 匹配变量引用格式：{{inputs.xxx}} 或 {{steps.node_id.output}}
 var varExtractRegex = regexp.MustCompile(`\{\{\s*([\w\.]+)\s*\}\}`)
 
-// Validate 执行全量静态语义校验
-func Validate(proto *RunlyProtocol) error {
-	// 1. 构建节点快速索引，用于 O(1) 查找
-	nodeMap := make(map[string]Node)
-	for _, node := range proto.Topology.Nodes {
-		nodeMap[node.ID] = node
+// VarRefs 按出现顺序返回文本中引用的全部变量路径 (去重)
+func VarRefs(text string) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, m := range varExtractRegex.FindAllStringSubmatch(text, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			refs = append(refs, m[1])
+		}
 	}
+	return refs
+}
 
-	// 2. 检查拓扑连通性（起始节点、逻辑分支、末端节点）
-	if err := validateTopology(proto, nodeMap); err != nil {
+// Validate 执行全量静态语义校验，应用规则配置后存在 error 级诊断时返回全部诊断
+func Validate(proto *RunlyProtocol) error {
+	diags, err := Check(proto)
+	if err != nil {
 		return err
 	}
+	if diags.HasErrors() {
+		return diags
+	}
+	return nil
+}
 
-	// 3. 检查变量引用一致性
-	if err := validateVariables(proto, nodeMap); err != nil {
-		return err
+// Check 执行全量静态语义校验，并按 .runlyrc 与内联注释调整规则级别
+func Check(proto *RunlyProtocol) (Diagnostics, error) {
+	cfg, err := LoadRuleConfig(proto.Source)
+	if err != nil {
+		return nil, err
+	}
+	return Analyze(proto).Configure(cfg), nil
+}
+
+// Analyze 执行全量静态语义校验，收集全部诊断而非在首个错误处返回
+// 诊断使用规则默认级别，未应用任何规则配置
+func Analyze(proto *RunlyProtocol) Diagnostics {
+	a := &analyzer{proto: proto, nodeMap: make(map[string]Node)}
+
+	// 0. 加载阶段的结构校验结果 (未知字段等非阻断诊断)
+	a.diags = append(a.diags, proto.Findings...)
+
+	// 1. 构建节点快速索引，用于 O(1) 查找，同时检查节点 ID 唯一性
+	for _, node := range proto.Topology.Nodes {
+		if _, dup := a.nodeMap[node.ID]; dup {
+			// 🪪 节点 ID [%s] 重复定义
+			a.report("RUN008", node.At("id"), i18n.T("errors.duplicate_node_id"), node.ID).
+				Suggestion = i18n.T("errors.hint_unique_id")
+			continue
+		}
+		a.nodeMap[node.ID] = node
+		a.nodeIDs = append(a.nodeIDs, node.ID)
 	}
 
+	// 2. 检查拓扑连通性（起始节点、逻辑分支、末端节点、可达性）
+	a.checkTopology()
+
+	// 3. 检查变量引用一致性
+	a.checkVariables()
+
 	// 4. 检查外部资源（Skills/Knowledge）引用有效性
-	if err := validateResourceLinks(proto, nodeMap); err != nil {
-		return err
+	a.checkResourceLinks()
+
+	// 5. 检查密钥占位符的写法与后端名称 (不读取密钥值，解析只在 run 时进行)
+	a.checkSecrets()
+
+	// 6. 检查资产元数据 (版本号格式)
+	a.checkManifest()
+
+	return a.diags
+}
+
+// analyzer 静态校验过程中的共享状态
+type analyzer struct {
+	proto   *RunlyProtocol
+	nodeMap map[string]Node
+	nodeIDs []string
+	diags   Diagnostics
+}
+
+// report 按规则默认级别记录一条诊断，返回该诊断以便补充修复建议
+func (a *analyzer) report(code string, pos Position, format string, args ...interface{}) *Diagnostic {
+	d := NewRuleDiagnostic(code, pos, format, args...)
+	a.diags = append(a.diags, d)
+	return d
+}
+
+// didYouMean 生成 "did you mean" 建议，无相近候选时使用兜底提示
+func didYouMean(target string, candidates []string, fallbackKey string) string {
+	if c := Closest(target, candidates); c != "" {
+		return fmt.Sprintf(i18n.T("errors.hint_did_you_mean"), c)
 	}
+	return i18n.T(fallbackKey)
+}
 
-	return nil
+// edgeTarget 一条跳转的目标节点及其在源文件中的位置
+type edgeTarget struct {
+	id  string
+	pos Position
+}
+
+// isTerminal 判断跳转目标是否为终点标记
+func isTerminal(id string) bool {
+	return id == "" || id == "terminate" || id == "terminate_error"
 }
 
-// validateTopology 验证拓扑结构的完整性
-func validateTopology(proto *RunlyProtocol, nodeMap map[string]Node) error {
+// edgesOf 收集节点所有潜在跳转路径及其源码位置
+func edgesOf(node Node) []edgeTarget {
+	targets := []edgeTarget{
+		{node.OnSuccess, node.At("on_success")},
+		{node.OnFailure, node.At("on_failure")},
+	}
+	if node.Type == "LOGIC_GATE" {
+		for _, rule := range node.Rules {
+			targets = append(targets, edgeTarget{rule.Next, rule.At("next")})
+		}
+	}
+	return targets
+}
+
+// checkTopology 验证拓扑结构的完整性
+func (a *analyzer) checkTopology() {
+	proto := a.proto
+
 	// 验证 StartAt 节点是否存在
-	if _, ok := nodeMap[proto.Topology.StartAt]; !ok {
+	startAt := proto.Topology.StartAt
+	if _, ok := a.nodeMap[startAt]; !ok {
 		// 🚩 拓扑起始节点 [%s] 未定义
-		return fmt.Errorf(i18n.T("errors.start_node_missing"), proto.Topology.StartAt)
+		a.report("RUN001", proto.Topology.At("start_at"), i18n.T("errors.start_node_missing"), startAt).
+			Suggestion = didYouMean(startAt, a.nodeIDs, "errors.hint_start_at")
 	}
 
-	// 遍历所有节点，验证其下游跳转 ID
+	// 遍历所有节点，验证下游跳转 ID
 	for _, node := range proto.Topology.Nodes {
-		// 收集所有潜在跳转路径
-		targets := []string{node.OnSuccess, node.OnFailure}
-		if node.Type == "LOGIC_GATE" {
-			for _, rule := range node.Rules {
-				targets = append(targets, rule.Next)
-			}
+		if node.Type == "LOGIC_GATE" && len(node.Rules) == 0 && node.OnSuccess == "" {
+			// 🔀 逻辑网关 [%s] 未定义任何规则
+			a.report("RUN011", node.Pos, i18n.T("errors.gate_no_rules"), node.ID).
+				Suggestion = i18n.T("errors.hint_gate_rules")
 		}
 
-		for _, t := range targets {
+		for _, t := range edgesOf(node) {
 			// 跳过终点标记
-			if t == "" || t == "terminate" || t == "terminate_error" {
+			if isTerminal(t.id) {
 				continue
 			}
 			// 检查下游节点是否存在
-			if _, exists := nodeMap[t]; !exists {
+			if _, exists := a.nodeMap[t.id]; !exists {
 				// 📍 节点 [%s] 引用了不存在的下游目标: %s
-				return fmt.Errorf(i18n.T("errors.node_not_found"), node.ID, t)
+				a.report("RUN002", t.pos, i18n.T("errors.node_not_found"), node.ID, t.id).
+					Suggestion = didYouMean(t.id, a.nodeIDs, "errors.hint_edge_target")
 			}
 		}
 	}
-	return nil
+
+	// 从起始节点出发做广度优先遍历，标记不可达节点
+	if _, ok := a.nodeMap[startAt]; !ok {
+		return
+	}
+	reached := map[string]bool{startAt: true}
+	queue := []string{startAt}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, t := range edgesOf(a.nodeMap[id]) {
+			if _, exists := a.nodeMap[t.id]; exists && !reached[t.id] {
+				reached[t.id] = true
+				queue = append(queue, t.id)
+			}
+		}
+	}
+	for _, id := range a.nodeIDs {
+		if !reached[id] {
+			// 🏝️ 节点 [%s] 无法从起始节点到达
+			a.report("RUN009", a.nodeMap[id].Pos, i18n.T("errors.node_unreachable"), id).
+				Suggestion = i18n.T("errors.hint_unreachable")
+		}
+	}
 }
 
-// validateVariables 验证所有变量引用的源头是否合法
-func validateVariables(proto *RunlyProtocol, nodeMap map[string]Node) error {
-	for _, node := range proto.Topology.Nodes {
-		// 序列化 Config 进行静态扫描，查找 {{...}} 占位符
-		rawConfig := fmt.Sprintf("%v", node.Config)
-		matches := varExtractRegex.FindAllStringSubmatch(rawConfig, -1)
-
-		for _, match := range matches {
-			path := match[1]
-			parts := strings.Split(path, ".")
-
-			switch parts[0] {
-			case "inputs":
-				// 检查 Dictionary.Inputs 域
-				if !hasInputParam(proto.Dictionary.Inputs, parts[1]) {
-					// ⌨️ 节点 [%s] 引用了 Dictionary 中未定义的输入参数: %s
-					return fmt.Errorf(i18n.T("errors.input_ref_missing"), node.ID, parts[1])
-				}
-			case "steps":
-				// 检查 Steps 引用格式及引用的节点是否存在
-				if len(parts) < 3 {
-					// 🔗 节点 [%s] 的变量引用格式错误: %s
-					return fmt.Errorf(i18n.T("errors.var_format_err"), node.ID, path)
-				}
-				refNodeID := parts[1]
-				if _, exists := nodeMap[refNodeID]; !exists {
-					// 📍 节点 [%s] 引用了不存在的对象: %s
-					return fmt.Errorf(i18n.T("errors.node_not_found"), node.ID, refNodeID)
+// checkVariables 验证所有变量引用的源头是否合法
+func (a *analyzer) checkVariables() {
+	inputNames := make([]string, 0, len(a.proto.Dictionary.Inputs))
+	for _, in := range a.proto.Dictionary.Inputs {
+		inputNames = append(inputNames, in.Name)
+	}
+
+	for _, node := range a.proto.Topology.Nodes {
+		// 逐个扫描 Config 字段，查找 {{...}} 占位符，并定位到具体的配置键
+		for _, key := range sortedKeys(node.Config) {
+			rawConfig := fmt.Sprintf("%v", node.Config[key])
+			pos := node.At("config." + key)
+			matches := varExtractRegex.FindAllStringSubmatch(rawConfig, -1)
+
+			for _, match := range matches {
+				path := match[1]
+				parts := strings.Split(path, ".")
+
+				switch parts[0] {
+				case "inputs":
+					// 检查 Dictionary.Inputs 域
+					if len(parts) < 2 || !hasInputParam(a.proto.Dictionary.Inputs, parts[1]) {
+						// ⌨️ 节点 [%s] 引用了 Dictionary 中未定义的输入参数: %s
+						d := a.report("RUN003", pos, i18n.T("errors.input_ref_missing"), path)
+						d.Suggestion = i18n.T("errors.hint_declare_input")
+						if len(parts) >= 2 {
+							d.Suggestion = didYouMean(parts[1], inputNames, "errors.hint_declare_input")
+						}
+					}
+				case "steps":
+					// 检查 Steps 引用格式及引用的节点是否存在
+					if len(parts) < 3 {
+						// 🔗 节点 [%s] 的变量引用格式错误: %s
+						a.report("RUN004", pos, i18n.T("errors.var_format_err"), path).
+							Suggestion = i18n.T("errors.hint_step_format")
+						continue
+					}
+					refNodeID := parts[1]
+					if _, exists := a.nodeMap[refNodeID]; !exists {
+						// 📍 节点 [%s] 引用了不存在的对象: %s
+						a.report("RUN005", pos, i18n.T("errors.node_not_found"), node.ID, refNodeID).
+							Suggestion = didYouMean(refNodeID, a.nodeIDs, "errors.hint_edge_target")
+					}
 				}
 			}
 		}
 	}
-	return nil
 }
 
-// validateResourceLinks 验证节点对 Skill 和 Knowledge 的引用
-func validateResourceLinks(proto *RunlyProtocol, nodeMap map[string]Node) error {
-	for _, node := range proto.Topology.Nodes {
+// checkManifest 验证资产版本号为严格的语义化版本，build 依据它判断版本升级是否合规
+func (a *analyzer) checkManifest() {
+	m := a.proto.Manifest
+	if m.Version != "" && !semver.Valid(m.Version) {
+		// 🔖 manifest.version [%s] 不是合法的语义化版本
+		a.report("RUN016", m.At("version"), i18n.T("errors.version_invalid"), m.Version).
+			Suggestion = i18n.T("errors.hint_semver")
+	}
+	if m.MinRuntime != "" && !semver.Valid(strings.TrimSpace(m.MinRuntime)) {
+		// 🔢 manifest.min_runtime 不是合法的语义化版本
+		a.report("RUN016", m.At("min_runtime"), i18n.T("errors.min_runtime_invalid"), m.MinRuntime).
+			Suggestion = i18n.T("errors.hint_semver")
+	}
+}
+
+// checkResourceLinks 验证节点对 Skill 和 Knowledge 的引用
+func (a *analyzer) checkResourceLinks() {
+	skillIDs := make([]string, 0, len(a.proto.Skills))
+	for _, s := range a.proto.Skills {
+		skillIDs = append(skillIDs, s.ID)
+	}
+	kbIDs := make([]string, 0, len(a.proto.Knowledge))
+	for _, k := range a.proto.Knowledge {
+		kbIDs = append(kbIDs, k.ID)
+	}
+
+	for _, node := range a.proto.Topology.Nodes {
 		// 技能引用检查
 		if node.Type == "SKILL_CALL" {
 			ref, _ := node.Config["skill_ref"].(string)
-			if !hasSkillID(proto.Skills, ref) {
+			if !hasSkillID(a.proto.Skills, ref) {
 				// 🛠️ 节点 [%s] 引用的技能 [%s] 未在 skills 域定义
-				return fmt.Errorf(i18n.T("errors.skill_ref_missing"), node.ID, ref)
+				a.report("RUN006", node.At("config.skill_ref"), i18n.T("errors.skill_ref_missing"), node.ID, ref).
+					Suggestion = didYouMean(ref, skillIDs, "errors.hint_declare_skill")
 			}
 		}
 
@@ -121,17 +284,81 @@ func validateResourceLinks(proto *RunlyProtocol, nodeMap map[string]Node) error
 		if node.Type == "AI_TASK" {
 			ref, ok := node.Config["knowledge_ref"].(string)
 			if ok && ref != "" {
-				if !hasKnowledgeID(proto.Knowledge, ref) {
+				if !hasKnowledgeID(a.proto.Knowledge, ref) {
 					// 📚 节点 [%s] 引用的知识库 [%s] 未在 knowledge 域定义
-					return fmt.Errorf(i18n.T("errors.kb_ref_missing"), node.ID, ref)
+					a.report("RUN007", node.At("config.knowledge_ref"), i18n.T("errors.kb_ref_missing"), node.ID, ref).
+						Suggestion = didYouMean(ref, kbIDs, "errors.hint_declare_kb")
 				}
 			}
 		}
 	}
-	return nil
+}
+
+// checkSecrets 检查密钥占位符的写法与后端名称，定位到源文件中首次出现的位置
+// 静态校验不得有副作用：不读取环境变量以外的任何后端，也不执行外部命令或提示输入口令
+func (a *analyzer) checkSecrets() {
+	for _, raw := range MalformedSecretRefs(a.proto) {
+		// 🔐 密钥引用写法错误: %s
+		a.report("RUN010", a.textPosition(raw), i18n.T("errors.secret_ref_invalid"), raw).
+			Suggestion = i18n.T("errors.hint_secret_syntax")
+	}
+
+	for _, ref := range SecretRefs(a.proto) {
+		if !secrets.Default.Has(ref.Provider) {
+			// 🔐 未知的密钥后端: %s
+			a.report("RUN010", a.secretPosition(ref), i18n.T("errors.secret_provider_unknown"), ref.Provider).
+				Suggestion = didYouMean(ref.Provider, secrets.Default.Names(), "errors.hint_secret_syntax")
+		}
+	}
+}
+
+// textPosition 在源文件中查找一段文本首次出现的位置
+func (a *analyzer) textPosition(text string) Position {
+	src, err := os.ReadFile(a.proto.Source)
+	if err != nil {
+		return Position{File: a.proto.Source}
+	}
+	for i, line := range strings.Split(string(src), "\n") {
+		if col := strings.Index(line, text); col >= 0 {
+			return Position{File: a.proto.Source, Line: i + 1, Column: col + 1}
+		}
+	}
+	return Position{File: a.proto.Source}
+}
+
+// secretPosition 在源文件中查找密钥引用首次出现的位置
+func (a *analyzer) secretPosition(ref SecretRef) Position {
+	pos := Position{File: a.proto.Source}
+	src, err := os.ReadFile(a.proto.Source)
+	if err != nil {
+		return pos
+	}
+	for i, line := range strings.Split(string(src), "\n") {
+		for _, loc := range secretRegex.FindAllStringSubmatchIndex(line, -1) {
+			m := make([]string, len(loc)/2)
+			for k := range m {
+				if loc[2*k] >= 0 {
+					m[k] = line[loc[2*k]:loc[2*k+1]]
+				}
+			}
+			if parseSecretRef(m) == ref {
+				return Position{File: a.proto.Source, Line: i + 1, Column: loc[0] + 1}
+			}
+		}
+	}
+	return pos
 }
 
 // 辅助查询逻辑
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func hasInputParam(params []Parameter, name string) bool {
 	for _, p := range params {
 		if p.Name == name {
@@ -158,3 +385,12 @@ func hasKnowledgeID(kb []KnowledgeResource, id string) bool {
 	}
 	return false
 }
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
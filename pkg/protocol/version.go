@@ -0,0 +1,100 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/semver"
+	"gopkg.in/yaml.v3"
+)
+
+// RuntimeVersion 当前 CLI 版本，由 cmd 在启动时注入，用于校验 manifest.min_runtime
+var RuntimeVersion = ""
+
+// specDecoder 某一协议规范版本的解码器：完成结构校验并解码为 RunlyProtocol
+// 返回的诊断为加载阶段的非阻断提示 (如宽松模式下的未知字段)
+type specDecoder func(src *source, opts LoadOptions) (*RunlyProtocol, Diagnostics, error)
+
+// specDecoders 按规范版本 (MAJOR.MINOR) 注册的解码器，格式演进时在 init 中追加新版本
+var specDecoders = make(map[string]specDecoder)
+
+func init() {
+	specDecoders["1.0"] = decodeV1
+	specDecoders["1.1"] = decodeV1 // 1.1 仅收紧写法 (见 pkg/migrate)，结构与 1.0 兼容
+}
+
+// SpecVersions 已支持的协议规范版本 (升序)
+func SpecVersions() []string {
+	versions := make([]string, 0, len(specDecoders))
+	for v := range specDecoders {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return semver.MustParse(versions[i]).LessThan(semver.MustParse(versions[j]))
+	})
+	return versions
+}
+
+// normalizeSpecVersion 将规范版本规整为 MAJOR.MINOR (如 "1" -> "1.0")
+func normalizeSpecVersion(s string) (string, error) {
+	v, err := semver.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor), nil
+}
+
+// detectSpecVersion 读取根节点的 spec_version，缺省时视为 1.0 (引入该字段之前的协议)
+func detectSpecVersion(doc *yaml.Node, path string) (string, specDecoder, error) {
+	version := legacySpecVersion
+	var pos Position
+	if n := mappingValue(documentRoot(doc), "spec_version"); n != nil && n.Value != "" {
+		pos = Position{File: path, Line: n.Line, Column: n.Column}
+		normalized, err := normalizeSpecVersion(n.Value)
+		if err != nil {
+			// 📐 无法识别的协议规范版本
+			return "", nil, newDiagnostic(pos, i18n.T("errors.spec_version_invalid"), n.Value)
+		}
+		version = normalized
+	}
+
+	decode, ok := specDecoders[version]
+	if !ok {
+		// 📐 协议规范版本高于当前 CLI 所支持的版本
+		d := newDiagnostic(pos, i18n.T("errors.spec_version_unsupported"), version, strings.Join(SpecVersions(), ", "))
+		d.Suggestion = i18n.T("errors.hint_upgrade_cli")
+		return "", nil, d
+	}
+	return version, decode, nil
+}
+
+// CheckRuntime 校验 manifest.min_runtime：资产要求更高版本的 CLI 时拒绝执行并提示升级
+// 只由 run 与 build 调用，check、inspect、diff、migrate 等只读或修复类命令不受限制；
+// 开发构建 (版本号无法解析) 不做限制
+func CheckRuntime(proto *RunlyProtocol) error {
+	required := strings.TrimSpace(proto.Manifest.MinRuntime)
+	if required == "" {
+		return nil
+	}
+	pos := proto.Manifest.At("min_runtime")
+
+	min, err := semver.Parse(required)
+	if err != nil {
+		// 🔢 manifest.min_runtime 不是合法的语义化版本
+		return newDiagnostic(pos, i18n.T("errors.min_runtime_invalid"), required)
+	}
+
+	current, err := semver.Parse(RuntimeVersion)
+	if err != nil {
+		return nil
+	}
+	if current.LessThan(min) {
+		// ⬆️ 该资产要求 runly-cli >= %s，当前版本为 %s
+		d := newDiagnostic(pos, i18n.T("errors.runtime_too_old"), min, current)
+		d.Suggestion = i18n.T("errors.hint_upgrade_cli")
+		return d
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/config"
+)
+
+// CommandProvider 调用外部命令获取密钥 (如 pass、op、vault CLI)
+// 命令取自 RUNLY_SECRET_CMD 或当前 Profile 的 secret_cmd，密钥名作为最后一个参数追加 (不得以 - 开头，避免被当作选项)，
+// 命令的标准输出 (去除首尾空白) 即为密钥值
+type CommandProvider struct {
+	Command string
+}
+
+// Lookup 实现 Provider
+func (p *CommandProvider) Lookup(name string) (string, error) {
+	if !ValidName(name) {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+
+	command := p.Command
+	if command == "" {
+		command = os.Getenv("RUNLY_SECRET_CMD")
+	}
+	if command == "" {
+		if cfg, err := config.LoadConfig(); err == nil {
+			command = cfg.GetActive().SecretCmd
+		}
+	}
+	if command == "" {
+		return "", errors.New("secret-cmd is not configured (set RUNLY_SECRET_CMD or 'runly-cli config set --secret-cmd')")
+	}
+
+	fields := strings.Fields(command)
+	cmd := exec.Command(fields[0], append(fields[1:], name)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	value := strings.TrimSpace(stdout.String())
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultDotenvFile dotenv 文件的默认路径 (相对当前目录)，可通过 RUNLY_DOTENV_FILE 覆盖
+const DefaultDotenvFile = ".env"
+
+// DotenvProvider 从 dotenv 文件读取密钥，文件在首次查询时加载
+type DotenvProvider struct {
+	Path string
+
+	once   sync.Once
+	values map[string]string
+	err    error
+}
+
+// Lookup 实现 Provider
+func (p *DotenvProvider) Lookup(name string) (string, error) {
+	p.once.Do(p.load)
+	if p.err != nil {
+		return "", p.err
+	}
+	if v, ok := p.values[name]; ok {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
+
+func (p *DotenvProvider) load() {
+	path := p.Path
+	if path == "" {
+		path = os.Getenv("RUNLY_DOTENV_FILE")
+	}
+	if path == "" {
+		path = DefaultDotenvFile
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		p.err = err
+		return
+	}
+	defer f.Close()
+
+	p.values, p.err = ParseDotenv(f)
+}
+
+// ParseDotenv 解析 KEY=VALUE 格式，支持注释、export 前缀与单/双引号
+func ParseDotenv(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, `"`):
+			if unq, err := strconv.Unquote(value); err == nil {
+				value = unq
+			}
+		case strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") && len(value) >= 2:
+			value = value[1 : len(value)-1]
+		default:
+			// 去除行尾注释
+			if idx := strings.Index(value, " #"); idx >= 0 {
+				value = strings.TrimSpace(value[:idx])
+			}
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
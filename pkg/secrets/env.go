@@ -0,0 +1,14 @@
+package secrets
+
+import "os"
+
+// EnvProvider 从进程环境变量读取密钥
+type EnvProvider struct{}
+
+// Lookup 实现 Provider
+func (EnvProvider) Lookup(name string) (string, error) {
+	if v, ok := os.LookupEnv(name); ok {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
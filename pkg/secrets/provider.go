@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+)
+
+// ErrNotFound 后端中不存在指定名称的密钥
+var ErrNotFound = errors.New("secret not found")
+
+// NamePattern 占位符 {{secret.<provider>.<name>}} 中 name 的语法；不得以 - 开头，否则 cmd 提供者会把它当作选项
+const NamePattern = `[a-zA-Z0-9_][a-zA-Z0-9_\-/]*`
+
+var nameRegex = regexp.MustCompile(`^` + NamePattern + `$`)
+
+// ValidName 判断密钥名称能否在占位符中引用
+func ValidName(name string) bool {
+	return nameRegex.MatchString(name)
+}
+
+// Provider 密钥后端：按名称返回密钥值
+type Provider interface {
+	Lookup(name string) (string, error)
+}
+
+// Registry 按名称索引的密钥后端集合，对应占位符 {{secret.<provider>.<name>}}
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry 创建空的后端集合
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[string]Provider)}
+}
+
+// Register 注册 (或替换) 一个后端
+func (r *Registry) Register(name string, p Provider) {
+	r.providers[name] = p
+}
+
+// Names 返回已注册的后端名称
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for n := range r.providers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has 判断后端是否已注册，不会读取任何密钥
+func (r *Registry) Has(provider string) bool {
+	_, ok := r.providers[provider]
+	return ok
+}
+
+// Lookup 从指定后端读取密钥，返回后端的原始错误
+func (r *Registry) Lookup(provider, name string) (string, error) {
+	p, ok := r.providers[provider]
+	if !ok {
+		// 🔐 未知的密钥后端: %s
+		return "", fmt.Errorf(i18n.T("errors.secret_provider_unknown"), provider)
+	}
+	return p.Lookup(name)
+}
+
+// Resolve 从指定后端读取密钥，错误信息中包含完整的引用名
+func (r *Registry) Resolve(provider, name string) (string, error) {
+	value, err := r.Lookup(provider, name)
+	if err != nil {
+		// 🔐 无法解析密钥 %s.%s: %v
+		return "", fmt.Errorf(i18n.T("errors.secret_unresolved"), provider, name, err)
+	}
+	return value, nil
+}
+
+// Default 全局后端集合：env、dotenv、vault、cmd
+var Default = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register("env", EnvProvider{})
+	r.Register("dotenv", &DotenvProvider{})
+	r.Register("vault", &VaultProvider{})
+	r.Register("cmd", &CommandProvider{})
+	return r
+}
@@ -0,0 +1,132 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/originbeat-inc/runly-cli/internal/config"
+	"github.com/originbeat-inc/runly-cli/pkg/crypto"
+)
+
+// ErrVaultLocked 未提供保险库口令
+var ErrVaultLocked = errors.New("vault is locked (set RUNLY_VAULT_PASSPHRASE)")
+
+// PassphraseFunc 获取保险库口令，CLI 可替换为交互式输入
+var PassphraseFunc = func() ([]byte, error) {
+	if p := os.Getenv("RUNLY_VAULT_PASSPHRASE"); p != "" {
+		return []byte(p), nil
+	}
+	return nil, ErrVaultLocked
+}
+
+// vaultFile 保险库文件格式：整个密钥表以口令加密后存储
+type vaultFile struct {
+	Version int               `json:"version"`
+	Box     *crypto.SealedBox `json:"box"`
+}
+
+// Vault 解密后的本地保险库
+type Vault struct {
+	Path    string
+	Secrets map[string]string
+}
+
+// OpenVault 打开保险库，文件不存在时返回空保险库
+func OpenVault(path string, passphrase []byte) (*Vault, error) {
+	v := &Vault{Path: path, Secrets: make(map[string]string)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f vaultFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Box == nil {
+		return nil, fmt.Errorf("%s: not a runly vault file", path)
+	}
+	plaintext, err := crypto.OpenWithPassphrase(f.Box, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(plaintext, &v.Secrets); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Save 使用口令重新加密并写回磁盘 (0600)
+func (v *Vault) Save(passphrase []byte) error {
+	plaintext, err := json.Marshal(v.Secrets)
+	if err != nil {
+		return err
+	}
+	box, err := crypto.SealWithPassphrase(plaintext, passphrase)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(vaultFile{Version: 1, Box: box}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.Path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(v.Path, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile 不会修改已存在文件的权限
+	return os.Chmod(v.Path, 0600)
+}
+
+// Names 返回保险库中的密钥名称
+func (v *Vault) Names() []string {
+	names := make([]string, 0, len(v.Secrets))
+	for n := range v.Secrets {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VaultProvider 从 ~/.runly/vault.json 读取密钥，首次查询时解锁
+type VaultProvider struct {
+	Path string
+
+	once  sync.Once
+	vault *Vault
+	err   error
+}
+
+// Lookup 实现 Provider
+func (p *VaultProvider) Lookup(name string) (string, error) {
+	p.once.Do(func() {
+		path := p.Path
+		if path == "" {
+			path = config.GetVaultPath()
+		}
+		passphrase, err := PassphraseFunc()
+		if err != nil {
+			p.err = err
+			return
+		}
+		p.vault, p.err = OpenVault(path, passphrase)
+	})
+	if p.err != nil {
+		return "", p.err
+	}
+	if v, ok := p.vault.Secrets[name]; ok {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
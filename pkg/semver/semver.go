@@ -0,0 +1,149 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version 语义化版本 (https://semver.org)，构建元数据 (+xxx) 不参与比较
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	Pre   string // 预发布标识，如 "beta.1"
+}
+
+// Parse 解析版本号，兼容 "v" 前缀与省略的次版本/修订号 (如 "1"、"1.2")
+func Parse(s string) (Version, error) {
+	raw := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+
+	var v Version
+	core, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		if pre == "" {
+			return Version{}, fmt.Errorf("invalid version %q", raw)
+		}
+		v.Pre = pre
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", raw)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version %q", raw)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// strictRegex 严格的 semver 2.0 写法：MAJOR.MINOR.PATCH，数字段无前导零，可带预发布与构建元数据
+var strictRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// Valid 判断 s 是否为严格的语义化版本号 (不接受 "v" 前缀与省略写法)，用于校验资产版本
+func Valid(s string) bool {
+	return strictRegex.MatchString(s)
+}
+
+// MustParse 解析版本号，失败时 panic，仅用于常量
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// String 返回规范写法 MAJOR.MINOR.PATCH[-PRE]
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// IncMajor 返回下一个主版本号，如 1.4.2 -> 2.0.0
+func (v Version) IncMajor() Version {
+	return Version{Major: v.Major + 1}
+}
+
+// IncMinor 返回下一个次版本号，如 1.4.2 -> 1.5.0
+func (v Version) IncMinor() Version {
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// IncPatch 返回下一个修订号，如 1.4.2 -> 1.4.3；预发布版本直接转为对应的正式版本
+func (v Version) IncPatch() Version {
+	if v.Pre != "" {
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	}
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// Compare 比较两个版本：a < b 返回 -1，相等返回 0，a > b 返回 1
+func Compare(a, b Version) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePre(a.Pre, b.Pre)
+}
+
+// LessThan 判断 v 是否低于 o
+func (v Version) LessThan(o Version) bool {
+	return Compare(v, o) < 0
+}
+
+// comparePre 按 semver 规则比较预发布标识：无标识高于有标识，逐段比较，数字段按数值比较且低于字母段
+func comparePre(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "" {
+		return 1
+	}
+	if b == "" {
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
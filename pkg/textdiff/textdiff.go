@@ -0,0 +1,138 @@
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind 差异片段类型
+type Kind int
+
+const (
+	Equal Kind = iota
+	Insert
+	Delete
+)
+
+// Op 一条差异操作：Equal 保留、Insert 仅在新版本中出现、Delete 仅在旧版本中出现
+type Op struct {
+	Kind Kind
+	Text string
+}
+
+// Diff 基于最长公共子序列计算两个序列的最小差异 (适用于行级或词级比较)
+func Diff(a, b []string) []Op {
+	// lcs[i][j] 表示 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []Op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Op{Equal, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, Op{Delete, a[i]})
+			i++
+		default:
+			ops = append(ops, Op{Insert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, Op{Delete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, Op{Insert, b[j]})
+	}
+	return ops
+}
+
+// Lines 将文本按行拆分，忽略末尾换行
+func Lines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Unified 生成 unified diff 格式的行级差异，context 为每个变更块保留的上下文行数
+// 两段文本一致时返回空字符串
+func Unified(oldName, newName, oldText, newText string, context int) string {
+	ops := Diff(Lines(oldText), Lines(newText))
+
+	// 标记需要输出的操作：变更行及其前后 context 行
+	show := make([]bool, len(ops))
+	changed := false
+	for k, op := range ops {
+		if op.Kind == Equal {
+			continue
+		}
+		changed = true
+		for d := max(0, k-context); d <= min(len(ops)-1, k+context); d++ {
+			show[d] = true
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+
+	oldLine, newLine := 1, 1
+	for k := 0; k < len(ops); {
+		if !show[k] {
+			if ops[k].Kind != Insert {
+				oldLine++
+			}
+			if ops[k].Kind != Delete {
+				newLine++
+			}
+			k++
+			continue
+		}
+
+		// 收集一个连续的变更块
+		end := k
+		oldCount, newCount := 0, 0
+		for end < len(ops) && show[end] {
+			if ops[end].Kind != Insert {
+				oldCount++
+			}
+			if ops[end].Kind != Delete {
+				newCount++
+			}
+			end++
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldLine, oldCount, newLine, newCount)
+		for ; k < end; k++ {
+			switch ops[k].Kind {
+			case Equal:
+				b.WriteString(" " + ops[k].Text + "\n")
+			case Delete:
+				b.WriteString("-" + ops[k].Text + "\n")
+			case Insert:
+				b.WriteString("+" + ops[k].Text + "\n")
+			}
+		}
+		oldLine += oldCount
+		newLine += newCount
+	}
+	return b.String()
+}
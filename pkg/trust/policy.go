@@ -0,0 +1,87 @@
+package trust
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Policy 签名策略：各角色至少需要的可信签名者数量，如 {creator: 1, reviewer: 1}
+type Policy map[string]int
+
+// ParsePolicy 解析命令行签名策略，格式为逗号分隔的 role[=n]，如 "creator,reviewer=2"
+func ParsePolicy(s string) (Policy, error) {
+	p := Policy{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		role, count := part, 1
+		if i := strings.Index(part, "="); i >= 0 {
+			n, err := strconv.Atoi(strings.TrimSpace(part[i+1:]))
+			if err != nil {
+				// ✍️ 签名策略无效
+				return nil, fmt.Errorf(i18n.T("errors.signature_policy_invalid"), part)
+			}
+			role, count = strings.TrimSpace(part[:i]), n
+		}
+		p[role] = count
+	}
+	return p, p.Validate()
+}
+
+// Validate 检查策略中的角色与数量是否合法
+func (p Policy) Validate() error {
+	for role, n := range p {
+		if !protocol.ValidRole(role) || n < 1 {
+			return fmt.Errorf(i18n.T("errors.signature_policy_invalid"), fmt.Sprintf("%s=%d", role, n))
+		}
+	}
+	return nil
+}
+
+// roleOrder 同一签名者以多个角色签名时，只计入排在最前的角色
+var roleOrder = []string{protocol.RoleCreator, protocol.RoleReviewer, protocol.RoleOrganization}
+
+// Unmet 按角色统计有效且可信的不同签名者，返回未满足的要求 (如 "reviewer 0/1")，按角色排序；
+// 不同角色必须由不同签名者满足，一个 MeID 只计入一个角色
+func (p Policy) Unmet(verdicts []*SignatureVerdict) []string {
+	held := make(map[string]map[string]bool) // MeID -> 有效且可信的角色
+	for _, v := range verdicts {
+		if !v.Valid || !v.Trusted {
+			continue
+		}
+		id := v.Signature.SignerID
+		if held[id] == nil {
+			held[id] = make(map[string]bool)
+		}
+		held[id][v.Signature.Role] = true
+	}
+
+	signers := make(map[string]map[string]bool)
+	for id, roles := range held {
+		for _, role := range roleOrder {
+			if roles[role] {
+				if signers[role] == nil {
+					signers[role] = make(map[string]bool)
+				}
+				signers[role][id] = true
+				break
+			}
+		}
+	}
+
+	var unmet []string
+	for role, n := range p {
+		if got := len(signers[role]); got < n {
+			unmet = append(unmet, fmt.Sprintf("%s %d/%d", role, got, n))
+		}
+	}
+	sort.Strings(unmet)
+	return unmet
+}
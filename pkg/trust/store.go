@@ -0,0 +1,192 @@
+package trust
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+)
+
+// 信任来源
+const (
+	SourcePinned   = "pinned"    // 通过 trust add 手动固定
+	SourceMeServer = "me-server" // trust add 时从身份服务器解析后固定，或验证时在线解析
+	SourceLocal    = "local"     // 当前 Profile 的本地身份
+)
+
+// Key 信任库中的一把公钥
+type Key struct {
+	MeID      string     `json:"me_id"`
+	PublicKey string     `json:"public_key"`
+	Name      string     `json:"name,omitempty"`  // 备注，如组织或审核人名称
+	Roles     []string   `json:"roles,omitempty"` // 授权的联署角色 (reviewer / organization)，creator 身份无需授权
+	Source    string     `json:"source"`
+	AddedAt   time.Time  `json:"added_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"` // 轮换时间，此后的签名不再接受
+	RevokedAt *time.Time `json:"revoked_at,omitempty"` // 吊销时间，此后的签名一律拒绝
+}
+
+// HasRole 判断公钥是否被授权以指定角色联署
+func (k Key) HasRole(role string) bool {
+	for _, r := range k.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokedError 签名时间晚于密钥的吊销或轮换时间
+type RevokedError struct {
+	msg string
+}
+
+func (e *RevokedError) Error() string { return e.msg }
+
+// ValidAt 判断密钥对某个签名是否仍然有效。claimed 为签名中声明的时间，由签名者控制，可以倒填；
+// trusted 为可信时间戳 (如 Hub 发布时间)，未知时为零值。
+// 吊销意味着私钥可能已泄露，只有可信时间早于吊销时间的签名才被接受，没有可信时间时一律拒绝；
+// 轮换不代表泄露，没有可信时间时按声明时间判断
+func (k Key) ValidAt(claimed, trusted time.Time) error {
+	if k.RevokedAt != nil && (trusted.IsZero() || !trusted.Before(*k.RevokedAt)) {
+		// 🚫 公钥 %s 已于 %s 吊销
+		return &RevokedError{fmt.Sprintf(i18n.T("errors.trust_key_revoked"), Short(k.PublicKey), k.RevokedAt.Format(time.RFC3339))}
+	}
+	signedAt := claimed
+	if !trusted.IsZero() {
+		signedAt = trusted
+	}
+	if k.RetiredAt != nil && (signedAt.IsZero() || !signedAt.Before(*k.RetiredAt)) {
+		// 🔁 公钥 %s 已于 %s 轮换
+		return &RevokedError{fmt.Sprintf(i18n.T("errors.trust_key_retired"), Short(k.PublicKey), k.RetiredAt.Format(time.RFC3339))}
+	}
+	return nil
+}
+
+// Store 本地信任库：MeID 到固定公钥的映射，同一 MeID 可以固定多把公钥
+type Store struct {
+	Path string `json:"-"`
+	Keys []Key  `json:"keys"`
+}
+
+// Load 读取信任库，文件不存在时返回空信任库
+func Load(path string) (*Store, error) {
+	s := &Store{Path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save 写回磁盘，按 MeID 排序保证文件稳定
+func (s *Store) Save() error {
+	sort.SliceStable(s.Keys, func(i, j int) bool { return s.Keys[i].MeID < s.Keys[j].MeID })
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+// Add 固定一把公钥，已存在时更新备注、来源、轮换与吊销时间，并合并授权角色
+func (s *Store) Add(k Key) error {
+	if err := ValidatePublicKey(k.PublicKey); err != nil {
+		return err
+	}
+	k.PublicKey = strings.ToLower(k.PublicKey)
+	if k.AddedAt.IsZero() {
+		k.AddedAt = time.Now().UTC()
+	}
+	for i, existing := range s.Keys {
+		if existing.MeID == k.MeID && existing.PublicKey == k.PublicKey {
+			s.Keys[i].Name = k.Name
+			s.Keys[i].Source = k.Source
+			for _, role := range k.Roles {
+				if !s.Keys[i].HasRole(role) {
+					s.Keys[i].Roles = append(s.Keys[i].Roles, role)
+				}
+			}
+			if k.RetiredAt != nil {
+				s.Keys[i].RetiredAt = k.RetiredAt
+			}
+			if k.RevokedAt != nil {
+				s.Keys[i].RevokedAt = k.RevokedAt
+			}
+			return nil
+		}
+	}
+	s.Keys = append(s.Keys, k)
+	return nil
+}
+
+// Remove 删除 MeID 的指定公钥，publicKey 为空时删除该 MeID 的全部公钥，返回删除数量
+func (s *Store) Remove(meID, publicKey string) int {
+	publicKey = strings.ToLower(publicKey)
+	kept := s.Keys[:0]
+	removed := 0
+	for _, k := range s.Keys {
+		if k.MeID == meID && (publicKey == "" || k.PublicKey == publicKey) {
+			removed++
+			continue
+		}
+		kept = append(kept, k)
+	}
+	s.Keys = kept
+	return removed
+}
+
+// For 返回 MeID 固定的全部公钥
+func (s *Store) For(meID string) []Key {
+	var keys []Key
+	for _, k := range s.Keys {
+		if k.MeID == meID {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Authorized 判断信任库是否授权该公钥以指定角色联署；联署角色只能来自 trust add --role，
+// 身份服务器能解析某个 MeID 仅说明其身份，不代表其是可信的审核人或组织
+func (s *Store) Authorized(meID, publicKey, role string) bool {
+	for _, k := range s.For(meID) {
+		if strings.EqualFold(k.PublicKey, publicKey) && k.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePublicKey 检查公钥为 32 字节的十六进制 Ed25519 公钥
+func ValidatePublicKey(publicKey string) error {
+	raw, err := hex.DecodeString(publicKey)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid Ed25519 public key %q (expected %d hex-encoded bytes)", publicKey, ed25519.PublicKeySize)
+	}
+	return nil
+}
+
+// Short 公钥的缩略显示形式
+func Short(publicKey string) string {
+	if len(publicKey) <= 16 {
+		return publicKey
+	}
+	return publicKey[:16] + "…"
+}
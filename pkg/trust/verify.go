@@ -0,0 +1,241 @@
+package trust
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/originbeat-inc/runly-cli/internal/i18n"
+	"github.com/originbeat-inc/runly-cli/pkg/compiler"
+	"github.com/originbeat-inc/runly-cli/pkg/protocol"
+)
+
+// Resolver 通过身份服务器解析 MeID 登记过的公钥 (含吊销时间)
+type Resolver func(meID string) ([]Key, error)
+
+// Verifier 判定资产签名者是否可信
+// 资产内嵌的 creator.pub_key 只能证明内容未被改动，任何人都可以换用自己的密钥重新签名，
+// 因此签名公钥必须来自信任库、本地身份或身份服务器
+type Verifier struct {
+	Store   *Store
+	Local   []Key    // 当前 Profile 的本地身份及轮换下来的历史密钥，视为可信
+	Resolve Resolver // 在线解析未固定的签名者并刷新已知公钥的吊销状态，为 nil 时仅使用本地数据
+
+	// TrustedTime 不由签名者控制的签名时间上限 (如 Hub 发布时间)，未知时为零值；
+	// 已吊销密钥的签名只有在可信时间早于吊销时间时才被接受
+	TrustedTime time.Time
+
+	resolved map[string]resolution // 本次验证中已在线解析的 MeID
+}
+
+// resolution 一次在线解析的结果
+type resolution struct {
+	keys []Key
+	err  error
+}
+
+// Verdict 验证结论：完整 (签名与内嵌公钥匹配) 与可信 (签名公钥属于可信签名者) 相互独立
+type Verdict struct {
+	Intact  bool
+	Trusted bool
+	MeID    string
+	PubKey  string
+	Source  string // 信任来源：pinned | me-server | local
+	Reason  error  // 不完整或不可信的原因
+}
+
+// Verify 先验证完整性，再判定签名公钥是否可信
+func (v *Verifier) Verify(proto *protocol.RunlyProtocol) *Verdict {
+	verdict := &Verdict{
+		MeID:   proto.Manifest.Creator.MeID,
+		PubKey: strings.ToLower(proto.Manifest.Creator.PubKey),
+	}
+	if ok, err := compiler.VerifyIntegrity(proto); !ok {
+		verdict.Reason = err
+		return verdict
+	}
+	verdict.Intact = true
+
+	verdict.Source, verdict.Reason = v.Trust(verdict.MeID, verdict.PubKey, signedAt(proto))
+	verdict.Trusted = verdict.Reason == nil
+	return verdict
+}
+
+// Revoked 判断签名是否晚于签名公钥的吊销或轮换时间
+func (vd *Verdict) Revoked() bool {
+	var revoked *RevokedError
+	return errors.As(vd.Reason, &revoked)
+}
+
+// signedAt 创建者声明的签名时间：优先取 creator 多方签名的时间，旧版资产取 manifest.updated_at；
+// 该时间由签名者控制，只用于判断轮换，吊销判断依赖 Verifier.TrustedTime
+func signedAt(proto *protocol.RunlyProtocol) time.Time {
+	for _, sig := range proto.Security.Signatures {
+		if sig.Role == protocol.RoleCreator && strings.EqualFold(sig.KeyID, proto.Manifest.Creator.PubKey) {
+			return sig.SignedAt
+		}
+	}
+	return proto.Manifest.UpdatedAt
+}
+
+// Trust 判断公钥在签名时间是否属于 MeID 的可信签名者，返回信任来源
+// 信任库中固定了该 MeID 时以固定公钥为准，但仍在线刷新其吊销状态；密钥已吊销或签名晚于轮换时间时返回 *RevokedError
+func (v *Verifier) Trust(meID, publicKey string, signedAt time.Time) (string, error) {
+	publicKey = strings.ToLower(publicKey)
+	if meID == "" {
+		// 🔐 资产未声明签名者 (manifest.creator.me_id)
+		return "", fmt.Errorf(i18n.T("errors.trust_no_signer"))
+	}
+
+	for _, k := range v.Local {
+		if k.MeID == meID && strings.EqualFold(k.PublicKey, publicKey) {
+			return SourceLocal, v.refresh(k).ValidAt(signedAt, v.TrustedTime)
+		}
+	}
+
+	if v.Store != nil {
+		if pinned := v.Store.For(meID); len(pinned) > 0 {
+			for _, k := range pinned {
+				if k.PublicKey == publicKey {
+					return k.Source, v.refresh(k).ValidAt(signedAt, v.TrustedTime)
+				}
+			}
+			// 🔐 签名者 [%s] 已固定其他公钥，资产签名公钥 %s 不在其中
+			return "", fmt.Errorf(i18n.T("errors.trust_key_mismatch"), meID, Short(publicKey))
+		}
+	}
+
+	if v.Resolve == nil {
+		// 🔐 签名者 [%s] 不在信任库中
+		return "", fmt.Errorf(i18n.T("errors.trust_unknown_signer"), meID)
+	}
+	keys, err := v.lookup(meID)
+	if err != nil {
+		// 🔐 签名者 [%s] 不在信任库中，且无法通过身份服务器确认
+		return "", fmt.Errorf(i18n.T("errors.trust_resolve_fail"), meID, err)
+	}
+	for _, k := range keys {
+		if strings.EqualFold(k.PublicKey, publicKey) {
+			return SourceMeServer, k.ValidAt(signedAt, v.TrustedTime)
+		}
+	}
+	// 🔐 公钥 %s 未在身份服务器上登记为 [%s] 的密钥
+	return "", fmt.Errorf(i18n.T("errors.trust_not_registered"), Short(publicKey), meID)
+}
+
+// lookup 在线解析 MeID 的公钥，同一次验证中每个 MeID 只请求一次
+func (v *Verifier) lookup(meID string) ([]Key, error) {
+	if r, ok := v.resolved[meID]; ok {
+		return r.keys, r.err
+	}
+	keys, err := v.Resolve(meID)
+	if v.resolved == nil {
+		v.resolved = make(map[string]resolution)
+	}
+	v.resolved[meID] = resolution{keys, err}
+	return keys, err
+}
+
+// refresh 以身份服务器公布的轮换与吊销时间更新本地已知的公钥 (信任库或本地历史)，取较早者，
+// 使其他设备上执行的 keys rotate / keys revoke 同样生效；离线或解析失败时沿用本地记录
+func (v *Verifier) refresh(k Key) Key {
+	if v.Resolve == nil {
+		return k
+	}
+	keys, err := v.lookup(k.MeID)
+	if err != nil {
+		return k
+	}
+	for _, r := range keys {
+		if strings.EqualFold(r.PublicKey, k.PublicKey) {
+			k.RetiredAt = earliest(k.RetiredAt, r.RetiredAt)
+			k.RevokedAt = earliest(k.RevokedAt, r.RevokedAt)
+		}
+	}
+	return k
+}
+
+// earliest 返回两个可选时间中较早的一个
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+// SignatureVerdict 一方签名的验证结论
+type SignatureVerdict struct {
+	Signature protocol.Signature
+	Valid     bool   // 签名与内容摘要匹配
+	Trusted   bool   // 签名公钥属于声明的签名者
+	Source    string // 信任来源
+	Reason    error  // 无效或不可信的原因
+}
+
+// VerifySignatures 逐一验证资产内嵌与额外提供 (分离签名文件) 的多方签名；
+// 资产没有内嵌 creator 签名 (旧版资产) 时，创建者签名计为一个 creator 签名
+func (v *Verifier) VerifySignatures(proto *protocol.RunlyProtocol, extra []protocol.Signature) ([]*SignatureVerdict, error) {
+	digest, err := compiler.Digest(proto)
+	if err != nil {
+		return nil, err
+	}
+
+	var verdicts []*SignatureVerdict
+	creator := proto.Manifest.Creator
+	if !hasRole(proto.Security.Signatures, protocol.RoleCreator) && proto.Security.Signature != "" {
+		primary := v.Verify(proto)
+		verdicts = append(verdicts, &SignatureVerdict{
+			Signature: protocol.Signature{SignerID: creator.MeID, KeyID: creator.PubKey, Algorithm: compiler.AlgoEd25519, Role: protocol.RoleCreator, Value: proto.Security.Signature},
+			Valid:     primary.Intact && !primary.Revoked(),
+			Trusted:   primary.Trusted,
+			Source:    primary.Source,
+			Reason:    primary.Reason,
+		})
+	}
+
+	sigs := append(append([]protocol.Signature{}, proto.Security.Signatures...), extra...)
+	for i := range sigs {
+		sv := &SignatureVerdict{Signature: sigs[i]}
+		verdicts = append(verdicts, sv)
+		if sv.Reason = compiler.VerifySignature(digest, &sigs[i]); sv.Reason != nil {
+			continue
+		}
+		if sigs[i].Role == protocol.RoleCreator && sigs[i].SignerID != creator.MeID {
+			// 🔐 creator 签名者与 manifest.creator.me_id 不一致
+			sv.Reason = fmt.Errorf(i18n.T("errors.cosign_creator_mismatch"), sigs[i].SignerID, creator.MeID)
+			continue
+		}
+		sv.Source, sv.Reason = v.Trust(sigs[i].SignerID, sigs[i].KeyID, sigs[i].SignedAt)
+		// 签名晚于密钥吊销或轮换时间时视为无效签名，而不仅是不可信
+		var revoked *RevokedError
+		sv.Valid = !errors.As(sv.Reason, &revoked)
+		if sv.Reason == nil && sigs[i].Role != protocol.RoleCreator {
+			sv.Reason = v.authorize(&sigs[i], creator.MeID)
+		}
+		sv.Trusted = sv.Reason == nil
+	}
+	return verdicts, nil
+}
+
+// authorize 联署签名的角色授权：创建者不能为自己的资产联署，审核人与组织必须在信任库中以该角色固定
+func (v *Verifier) authorize(sig *protocol.Signature, creatorID string) error {
+	if sig.SignerID == creatorID {
+		// 🪞 创建者 [%s] 不能以 %s 身份为自己的资产联署
+		return fmt.Errorf(i18n.T("errors.cosign_self_review"), sig.SignerID, sig.Role)
+	}
+	if v.Store == nil || !v.Store.Authorized(sig.SignerID, sig.KeyID, sig.Role) {
+		// 🔐 签名者 [%s] 未在信任库中授权为 %s
+		return fmt.Errorf(i18n.T("errors.trust_role_not_pinned"), sig.SignerID, sig.Role, sig.SignerID, sig.Role)
+	}
+	return nil
+}
+
+func hasRole(sigs []protocol.Signature, role string) bool {
+	for _, sig := range sigs {
+		if sig.Role == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package trust
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testMeID = "me_carol"
+	testKey  = "0f2616a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d"
+)
+
+func mustTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return at
+}
+
+// pinnedStore 固定 testKey 的信任库
+func pinnedStore(t *testing.T, revokedAt *time.Time) *Store {
+	t.Helper()
+	s, err := Load(filepath.Join(t.TempDir(), "trust.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Key{MeID: testMeID, PublicKey: testKey, Source: SourcePinned, RevokedAt: revokedAt}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func isRevoked(err error) bool {
+	var revoked *RevokedError
+	return errors.As(err, &revoked)
+}
+
+// 签名时间由签名者声明，倒填到吊销之前也不能绕过吊销
+func TestTrustRejectsBackdatedSignature(t *testing.T) {
+	revokedAt := mustTime(t, "2026-03-01T00:00:00Z")
+	v := &Verifier{Store: pinnedStore(t, &revokedAt)}
+
+	if _, err := v.Trust(testMeID, testKey, mustTime(t, "2026-01-01T00:00:00Z")); !isRevoked(err) {
+		t.Errorf("backdated signature should be rejected, got %v", err)
+	}
+
+	// 可信时间 (如 Hub 发布时间) 早于吊销时才接受
+	v.TrustedTime = mustTime(t, "2026-02-01T00:00:00Z")
+	if _, err := v.Trust(testMeID, testKey, time.Time{}); err != nil {
+		t.Errorf("signature published before the revocation should be accepted, got %v", err)
+	}
+	v.TrustedTime = mustTime(t, "2026-04-01T00:00:00Z")
+	if _, err := v.Trust(testMeID, testKey, mustTime(t, "2026-01-01T00:00:00Z")); !isRevoked(err) {
+		t.Errorf("signature published after the revocation should be rejected, got %v", err)
+	}
+}
+
+// 固定的公钥同样在线刷新吊销状态，同一 MeID 只解析一次
+func TestTrustRefreshesPinnedRevocation(t *testing.T) {
+	revokedAt := mustTime(t, "2026-03-01T00:00:00Z")
+	calls := 0
+	v := &Verifier{
+		Store: pinnedStore(t, nil),
+		Resolve: func(meID string) ([]Key, error) {
+			calls++
+			return []Key{{MeID: meID, PublicKey: strings.ToUpper(testKey), RevokedAt: &revokedAt}}, nil
+		},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := v.Trust(testMeID, testKey, time.Time{}); !isRevoked(err) {
+			t.Errorf("pinned key revoked on the Me server should be rejected, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("resolver called %d times, want 1", calls)
+	}
+
+	// 离线时沿用本地记录
+	offline := &Verifier{Store: pinnedStore(t, nil)}
+	if source, err := offline.Trust(testMeID, testKey, time.Time{}); err != nil || source != SourcePinned {
+		t.Errorf("offline: source=%q err=%v", source, err)
+	}
+
+	// 解析失败不影响已固定的公钥
+	failing := &Verifier{
+		Store:   pinnedStore(t, nil),
+		Resolve: func(string) ([]Key, error) { return nil, errors.New("unreachable") },
+	}
+	if _, err := failing.Trust(testMeID, testKey, time.Time{}); err != nil {
+		t.Errorf("resolver failure should fall back to the pinned key, got %v", err)
+	}
+}
+
+// 身份服务器公布的轮换时间同样作用于固定的公钥：轮换之后的签名被拒绝，之前的仍然有效
+func TestTrustRefreshesPinnedRetirement(t *testing.T) {
+	retiredAt := mustTime(t, "2026-03-01T00:00:00Z")
+	v := &Verifier{
+		Store: pinnedStore(t, nil),
+		Resolve: func(meID string) ([]Key, error) {
+			return []Key{{MeID: meID, PublicKey: testKey, RetiredAt: &retiredAt}}, nil
+		},
+	}
+	if _, err := v.Trust(testMeID, testKey, mustTime(t, "2026-04-01T00:00:00Z")); !isRevoked(err) {
+		t.Errorf("signature after the rotation should be rejected, got %v", err)
+	}
+	if _, err := v.Trust(testMeID, testKey, mustTime(t, "2026-02-01T00:00:00Z")); err != nil {
+		t.Errorf("signature before the rotation should be accepted, got %v", err)
+	}
+}